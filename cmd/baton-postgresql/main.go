@@ -6,9 +6,12 @@ import (
 	"os"
 
 	cfg "github.com/conductorone/baton-postgresql/pkg/config"
+	"github.com/conductorone/baton-sdk/pkg/cli"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/conductorone/baton-sdk/pkg/types"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/conductorone/baton-postgresql/pkg/connector"
@@ -20,7 +23,40 @@ var version = "dev"
 func main() {
 	ctx := context.Background()
 
-	_, cmd, err := configschema.DefineConfiguration(ctx, "baton-postgresql", getConnector, cfg.Config)
+	v, cmd, err := configschema.DefineConfiguration(ctx, "baton-postgresql", getConnector, cfg.Config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	_, err = cli.AddCommand(cmd, v, &cfg.Config, &cobra.Command{
+		Use:   "sweep-expired-grants",
+		Short: "Revoke time-bound grants that have expired",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := v.BindPFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			if err := field.Validate(cfg.Config, v); err != nil {
+				return err
+			}
+
+			pgc, err := cli.MakeGenericConfiguration[*cfg.Postgresql](v)
+			if err != nil {
+				return err
+			}
+
+			cb, err := newConnector(ctx, pgc)
+			if err != nil {
+				return err
+			}
+
+			revoked, err := cb.SweepExpiredGrants(ctx)
+			fmt.Fprintf(os.Stdout, "revoked %d expired grants\n", revoked)
+			return err
+		},
+	})
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
@@ -38,7 +74,7 @@ func main() {
 func getConnector(ctx context.Context, pgc *cfg.Postgresql) (types.ConnectorServer, error) {
 	l := ctxzap.Extract(ctx)
 
-	cb, err := connector.New(ctx, pgc.Dsn, pgc.Schemas, pgc.IncludeColumns, pgc.IncludeLargeObjects, pgc.SyncAllDatabases, pgc.SkipBuiltInFunctions)
+	cb, err := newConnector(ctx, pgc)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
@@ -50,5 +86,26 @@ func getConnector(ctx context.Context, pgc *cfg.Postgresql) (types.ConnectorServ
 		return nil, err
 	}
 
-	return newConnector, nil
+	return cb.ReleaseCacheAfterSync(newConnector), nil
+}
+
+func newConnector(ctx context.Context, pgc *cfg.Postgresql) (*connector.Postgresql, error) {
+	return connector.New(
+		ctx,
+		pgc.Dsn,
+		pgc.Schemas,
+		pgc.IncludeColumns,
+		pgc.IncludeLargeObjects,
+		pgc.SyncAllDatabases,
+		pgc.SkipBuiltInFunctions,
+		connector.WithAllowSchemaCascade(pgc.AllowSchemaCascade),
+		connector.WithSchemaOwner(pgc.SchemaOwner),
+		connector.WithGroupNoLoginRoles(pgc.GroupNologinRoles),
+		connector.WithGroupRolePattern(pgc.GroupRolePattern),
+		connector.WithSuccessorRole(pgc.SuccessorRole),
+		connector.WithLoginNameTemplate(pgc.LoginNameTemplate),
+		connector.WithEmailSources(pgc.EmailSources, pgc.EmailSecurityLabelProvider, pgc.EmailLookupQuery),
+		connector.WithAccountTypeRules(pgc.AccountTypeRules),
+		connector.WithProtectedRoles(pgc.ProtectedRoles),
+	)
 }
@@ -10,6 +10,17 @@ type Postgresql struct {
 	IncludeLargeObjects bool `mapstructure:"include-large-objects"`
 	SyncAllDatabases bool `mapstructure:"sync-all-databases"`
 	SkipBuiltInFunctions bool `mapstructure:"skip-built-in-functions"`
+	AllowSchemaCascade bool `mapstructure:"allow-schema-cascade"`
+	SchemaOwner string `mapstructure:"schema-owner"`
+	GroupNologinRoles bool `mapstructure:"group-nologin-roles"`
+	GroupRolePattern string `mapstructure:"group-role-pattern"`
+	SuccessorRole string `mapstructure:"successor-role"`
+	LoginNameTemplate string `mapstructure:"login-name-template"`
+	EmailSources []string `mapstructure:"email-sources"`
+	EmailSecurityLabelProvider string `mapstructure:"email-security-label-provider"`
+	EmailLookupQuery string `mapstructure:"email-lookup-query"`
+	AccountTypeRules []string `mapstructure:"account-type-rules"`
+	ProtectedRoles []string `mapstructure:"protected-roles"`
 }
 
 func (c* Postgresql) findFieldByTag(tagValue string) (any, bool) {
@@ -11,11 +11,24 @@ var (
 	includeLargeObjects  = field.BoolField("include-large-objects", field.WithDescription("Include large objects when syncing. This can result in large amounts of data"))
 	syncAllDatabases     = field.BoolField("sync-all-databases", field.WithDescription("Sync all databases. This can result in large amounts of data"), field.WithDefaultValue(false))
 	skipBuiltInFunctions = field.BoolField("skip-built-in-functions", field.WithDescription("Skip postgres built in functions"), field.WithDefaultValue(false))
+	allowSchemaCascade   = field.BoolField("allow-schema-cascade", field.WithDisplayName("Allow schema cascade"), field.WithDescription("Allow deleting non-empty schemas, dropping every object they contain"), field.WithDefaultValue(false))
+	schemaOwner          = field.StringField("schema-owner", field.WithDisplayName("Schema owner"), field.WithDescription("The role that owns the schemas the connector creates. Defaults to the role the connector connects as"))
+	groupNoLoginRoles    = field.BoolField("group-nologin-roles", field.WithDisplayName("Group NOLOGIN roles"), field.WithDescription("Treat NOLOGIN roles as groups, even when they have no members"), field.WithDefaultValue(false))
+	groupRolePattern     = field.StringField("group-role-pattern", field.WithDisplayName("Group role pattern"), field.WithDescription("Treat roles whose name matches this regular expression as groups, even when they have no members"))
+	successorRole        = field.StringField("successor-role", field.WithDisplayName("Successor role"), field.WithDescription("When deleting a role, reassign the objects it owns in every database to this role instead of refusing to delete it"))
+	loginNameTemplate    = field.StringField("login-name-template", field.WithDisplayName("Login name template"), field.WithDescription("Go template deriving the name of provisioned roles from the account profile, e.g. 'app_{{ .email | localpart | identifier }}'"), field.WithDefaultValue("{{ .email }}"))
+	emailSources         = field.StringSliceField("email-sources", field.WithDisplayName("Email sources"), field.WithDefaultValue([]string{"name", "comment"}), field.WithDescription("Where to find a role's email, in order: name, comment, security-label, query"))
+	emailLabelProvider   = field.StringField("email-security-label-provider", field.WithDisplayName("Email security label provider"), field.WithDescription("Only read emails from security labels set by this provider"))
+	emailLookupQuery     = field.StringField("email-lookup-query", field.WithDisplayName("Email lookup query"), field.WithDescription("Query returning the role name and email of the role names passed in $1 as a text[], used by the query email source"))
+	accountTypeRules     = field.StringSliceField("account-type-rules", field.WithDisplayName("Account type rules"), field.WithDescription("Rules classifying roles as human, service or system accounts, tried in order, e.g. system:name=^rds_, service:attribute=replication"))
+	protectedRoles       = field.StringSliceField("protected-roles", field.WithDisplayName("Protected roles"), field.WithDefaultValue([]string{"postgres", "rds_superuser", "rdsadmin", "azure_pg_admin", "cloudsqlsuperuser"}), field.WithDescription("Roles that are never deleted, rotated or have privileges revoked. The connector's own role and pg_* roles are always protected"))
 )
 
 var relationships = []field.SchemaFieldRelationship{}
 
 //go:generate go run ./gen
 var Config = field.NewConfiguration([]field.SchemaField{
-	dsn, schemas, includeColumns, includeLargeObjects, syncAllDatabases, skipBuiltInFunctions,
+	dsn, schemas, includeColumns, includeLargeObjects, syncAllDatabases, skipBuiltInFunctions, allowSchemaCascade, schemaOwner,
+	groupNoLoginRoles, groupRolePattern, successorRole, loginNameTemplate, emailSources, emailLabelProvider, emailLookupQuery,
+	accountTypeRules, protectedRoles,
 }, relationships...)
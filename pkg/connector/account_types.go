@@ -0,0 +1,170 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+var accountTypes = map[string]v2.UserTrait_AccountType{
+	"human":   v2.UserTrait_ACCOUNT_TYPE_HUMAN,
+	"service": v2.UserTrait_ACCOUNT_TYPE_SERVICE,
+	"system":  v2.UserTrait_ACCOUNT_TYPE_SYSTEM,
+}
+
+// Kinds of account type rule, matched against the role.
+const (
+	// accountTypeRuleName matches the role name against a regular expression.
+	accountTypeRuleName = "name"
+	// accountTypeRuleComment matches roles whose COMMENT contains a tag, e.g. #service.
+	accountTypeRuleComment = "comment"
+	// accountTypeRuleAttribute matches roles with an attribute such as REPLICATION or NOLOGIN.
+	accountTypeRuleAttribute = "attribute"
+	// accountTypeRuleMember matches direct members of a marker role.
+	accountTypeRuleMember = "member"
+)
+
+var roleAttributes = map[string]func(r *postgres.RoleModel) bool{
+	"login":       func(r *postgres.RoleModel) bool { return r.CanLogin },
+	"nologin":     func(r *postgres.RoleModel) bool { return !r.CanLogin },
+	"replication": func(r *postgres.RoleModel) bool { return r.Replication },
+	"superuser":   func(r *postgres.RoleModel) bool { return r.Superuser },
+	"createrole":  func(r *postgres.RoleModel) bool { return r.CreateRole },
+	"createdb":    func(r *postgres.RoleModel) bool { return r.CreateDb },
+	"bypassrls":   func(r *postgres.RoleModel) bool { return r.BypassRowSecurity },
+}
+
+type accountTypeRule struct {
+	accountType v2.UserTrait_AccountType
+	kind        string
+	value       string
+	pattern     *regexp.Regexp
+}
+
+// parseAccountTypeRule parses a rule written as <type>:<kind>=<value>, e.g. system:name=^rds_ or
+// service:member=app_accounts.
+func parseAccountTypeRule(text string) (*accountTypeRule, error) {
+	typeName, match, ok := strings.Cut(text, ":")
+	if !ok {
+		return nil, fmt.Errorf("baton-postgres: invalid account type rule %q, expected <type>:<kind>=<value>", text)
+	}
+	kind, value, ok := strings.Cut(match, "=")
+	if !ok || value == "" {
+		return nil, fmt.Errorf("baton-postgres: invalid account type rule %q, expected <type>:<kind>=<value>", text)
+	}
+
+	accountType, ok := accountTypes[strings.ToLower(strings.TrimSpace(typeName))]
+	if !ok {
+		return nil, fmt.Errorf("baton-postgres: invalid account type in rule %q, expected human, service or system", text)
+	}
+
+	rule := &accountTypeRule{
+		accountType: accountType,
+		kind:        strings.ToLower(strings.TrimSpace(kind)),
+		value:       value,
+	}
+
+	switch rule.kind {
+	case accountTypeRuleName:
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("baton-postgres: invalid name pattern in account type rule %q: %w", text, err)
+		}
+		rule.pattern = pattern
+	case accountTypeRuleAttribute:
+		rule.value = strings.ToLower(value)
+		if _, ok := roleAttributes[rule.value]; !ok {
+			return nil, fmt.Errorf("baton-postgres: unknown role attribute in account type rule %q", text)
+		}
+	case accountTypeRuleComment, accountTypeRuleMember:
+	default:
+		return nil, fmt.Errorf("baton-postgres: unknown rule kind in account type rule %q, expected name, comment, attribute or member", text)
+	}
+
+	return rule, nil
+}
+
+func (a *accountTypeRule) matches(roleModel *postgres.RoleModel, markerRoles map[string]int64) bool {
+	switch a.kind {
+	case accountTypeRuleName:
+		return a.pattern.MatchString(roleModel.Name)
+	case accountTypeRuleComment:
+		return roleModel.Comment != nil && strings.Contains(*roleModel.Comment, a.value)
+	case accountTypeRuleAttribute:
+		return roleAttributes[a.value](roleModel)
+	case accountTypeRuleMember:
+		markerID, ok := markerRoles[a.value]
+		if !ok {
+			return false
+		}
+		for _, id := range roleModel.MemberOf {
+			if id == markerID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// accountTypeClassifier assigns an account type to roles. Rules are tried in order and the first match wins. Roles
+// no rule matches are SYSTEM if they are built in, HUMAN if they can log in and SERVICE otherwise.
+type accountTypeClassifier struct {
+	rules []*accountTypeRule
+}
+
+func newAccountTypeClassifier(rules []string) (*accountTypeClassifier, error) {
+	c := &accountTypeClassifier{}
+	for _, text := range rules {
+		rule, err := parseAccountTypeRule(text)
+		if err != nil {
+			return nil, err
+		}
+		c.rules = append(c.rules, rule)
+	}
+
+	return c, nil
+}
+
+// markerRoles returns the IDs of the roles used by member rules. Roles that don't exist are left out, so rules
+// naming them never match.
+func (c *accountTypeClassifier) markerRoles(ctx context.Context, client *postgres.Client) (map[string]int64, error) {
+	var names []string
+	for _, rule := range c.rules {
+		if rule.kind == accountTypeRuleMember {
+			names = append(names, rule.value)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	return client.GetRoleIDsByName(ctx, names)
+}
+
+func (c *accountTypeClassifier) classify(roleModel *postgres.RoleModel, markerRoles map[string]int64) v2.UserTrait_AccountType {
+	for _, rule := range c.rules {
+		if rule.matches(roleModel, markerRoles) {
+			return rule.accountType
+		}
+	}
+
+	switch {
+	case isBuiltInRole(roleModel.Name):
+		return v2.UserTrait_ACCOUNT_TYPE_SYSTEM
+	case roleModel.CanLogin:
+		return v2.UserTrait_ACCOUNT_TYPE_HUMAN
+	default:
+		return v2.UserTrait_ACCOUNT_TYPE_SERVICE
+	}
+}
+
+// isBuiltInRole reports whether a role is the bootstrap superuser or one of the predefined pg_ roles.
+func isBuiltInRole(name string) bool {
+	return name == "postgres" || strings.HasPrefix(name, "pg_")
+}
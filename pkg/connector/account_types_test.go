@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+func TestAccountTypeDefaults(t *testing.T) {
+	c, err := newAccountTypeClassifier(nil)
+	require.NoError(t, err)
+
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SYSTEM, c.classify(&postgres.RoleModel{Name: "postgres", CanLogin: true}, nil))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SYSTEM, c.classify(&postgres.RoleModel{Name: "pg_read_all_data"}, nil))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_HUMAN, c.classify(&postgres.RoleModel{Name: "jdoe", CanLogin: true}, nil))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SERVICE, c.classify(&postgres.RoleModel{Name: "readonly"}, nil))
+}
+
+func TestAccountTypeRules(t *testing.T) {
+	c, err := newAccountTypeClassifier([]string{
+		"system:name=^rds_",
+		"service:comment=#service",
+		"service:attribute=REPLICATION",
+		"service:member=app_accounts",
+		"human:attribute=nologin",
+	})
+	require.NoError(t, err)
+
+	comment := "reporting #service"
+	markers := map[string]int64{"app_accounts": 42}
+
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SYSTEM, c.classify(&postgres.RoleModel{Name: "rds_superuser", CanLogin: true}, markers))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SERVICE, c.classify(&postgres.RoleModel{Name: "reporting", CanLogin: true, Comment: &comment}, markers))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SERVICE, c.classify(&postgres.RoleModel{Name: "replicator", CanLogin: true, Replication: true}, markers))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_SERVICE, c.classify(&postgres.RoleModel{Name: "app", CanLogin: true, MemberOf: []int64{42}}, markers))
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_HUMAN, c.classify(&postgres.RoleModel{Name: "jdoe"}, markers))
+	// A member rule whose marker role doesn't exist never matches.
+	require.Equal(t, v2.UserTrait_ACCOUNT_TYPE_HUMAN, c.classify(&postgres.RoleModel{Name: "app", CanLogin: true, MemberOf: []int64{42}}, nil))
+
+	for _, rule := range []string{"robot:name=.*", "service:name", "service:name=(", "service:attribute=sleepy", "service:owner=x"} {
+		_, err := newAccountTypeClassifier([]string{rule})
+		require.Error(t, err, rule)
+	}
+}
@@ -0,0 +1,145 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+const (
+	disableAccountActionName = "disable_account"
+	enableAccountActionName  = "enable_account"
+)
+
+var roleIDActionArgument = &config.Field{
+	Name:        "role_id",
+	DisplayName: "Role ID",
+	Description: "The resource ID of the role to act on, e.g. role:16384",
+	IsRequired:  true,
+	Field:       &config.Field_StringField{StringField: &config.StringField{}},
+}
+
+var accountActionSchemas = map[string]*v2.BatonActionSchema{
+	disableAccountActionName: {
+		Name:        disableAccountActionName,
+		DisplayName: "Disable account",
+		Description: "Stops a role from logging in by setting NOLOGIN and terminates its open sessions",
+		Arguments: []*config.Field{
+			roleIDActionArgument,
+			{
+				Name:        "expire",
+				DisplayName: "Expire password",
+				Description: "Also set VALID UNTIL 'now' on the role",
+				Field:       &config.Field_BoolField{BoolField: &config.BoolField{}},
+			},
+		},
+		ReturnTypes: []*config.Field{
+			{
+				Name:        "terminated_sessions",
+				DisplayName: "Terminated sessions",
+				Field:       &config.Field_IntField{IntField: &config.IntField{}},
+			},
+		},
+	},
+	enableAccountActionName: {
+		Name:        enableAccountActionName,
+		DisplayName: "Enable account",
+		Description: "Lets a role disabled with disable_account log in again by restoring the LOGIN and VALID UNTIL it had before",
+		Arguments: []*config.Field{
+			roleIDActionArgument,
+		},
+	},
+}
+
+// actionManager runs the account actions. Actions complete before InvokeAction returns, so there is no status
+// to look up afterwards.
+type actionManager struct {
+	client *postgres.Client
+}
+
+func (a *actionManager) ListActionSchemas(ctx context.Context) ([]*v2.BatonActionSchema, annotations.Annotations, error) {
+	return []*v2.BatonActionSchema{
+		accountActionSchemas[disableAccountActionName],
+		accountActionSchemas[enableAccountActionName],
+	}, nil, nil
+}
+
+func (a *actionManager) GetActionSchema(ctx context.Context, name string) (*v2.BatonActionSchema, annotations.Annotations, error) {
+	schema, ok := accountActionSchemas[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("baton-postgres: unknown action %s", name)
+	}
+
+	return schema, nil, nil
+}
+
+func (a *actionManager) InvokeAction(
+	ctx context.Context,
+	name string,
+	args *structpb.Struct,
+) (string, v2.BatonActionStatus, *structpb.Struct, annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	roleIDStr := args.GetFields()["role_id"].GetStringValue()
+	if roleIDStr == "" {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, fmt.Errorf("baton-postgres: role_id is required")
+	}
+
+	roleID, err := parseObjectID(roleIDStr)
+	if err != nil {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+	}
+
+	pgRole, err := a.client.GetRole(ctx, roleID)
+	if err != nil {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+	}
+
+	var response *structpb.Struct
+	switch name {
+	case disableAccountActionName:
+		expire := args.GetFields()["expire"].GetBoolValue()
+		terminated, err := a.client.DisableRole(ctx, pgRole.Name, expire)
+		if err != nil {
+			return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+		}
+		l.Info("disabled role", zap.String("role", pgRole.Name), zap.Int("terminated_sessions", terminated))
+
+		response, err = structpb.NewStruct(map[string]interface{}{
+			"terminated_sessions": terminated,
+		})
+		if err != nil {
+			return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+		}
+	case enableAccountActionName:
+		err = a.client.EnableRole(ctx, pgRole.Name)
+		if err != nil {
+			return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, err
+		}
+		l.Info("enabled role", zap.String("role", pgRole.Name))
+
+		response = &structpb.Struct{}
+	default:
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, fmt.Errorf("baton-postgres: unknown action %s", name)
+	}
+
+	return fmt.Sprintf("%s:%s", name, roleIDStr), v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, response, nil, nil
+}
+
+func (a *actionManager) GetActionStatus(ctx context.Context, id string) (v2.BatonActionStatus, string, *structpb.Struct, annotations.Annotations, error) {
+	return v2.BatonActionStatus_BATON_ACTION_STATUS_UNKNOWN, "", nil, nil, fmt.Errorf("baton-postgres: actions run synchronously, no status is kept for %s", id)
+}
+
+func newActionManager(ctx context.Context, c *postgres.ClientDatabasesPool) *actionManager {
+	return &actionManager{
+		client: c.Default(ctx),
+	}
+}
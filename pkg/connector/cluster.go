@@ -0,0 +1,274 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+)
+
+var clusterResourceType = &v2.ResourceType{
+	Id:          "cluster",
+	DisplayName: "Cluster",
+	Traits:      nil,
+	Annotations: nil,
+}
+
+// roleAttributeEntitlements maps the role attribute entitlement slugs to the attribute they toggle.
+var roleAttributeEntitlements = map[string]string{
+	"superuser":   postgres.RoleAttributeSuperuser,
+	"create-db":   postgres.RoleAttributeCreateDb,
+	"create-role": postgres.RoleAttributeCreateRole,
+	"bypass-rls":  postgres.RoleAttributeBypassRLS,
+	"replication": postgres.RoleAttributeReplication,
+}
+
+type clusterSyncer struct {
+	resourceType *v2.ResourceType
+	client       *postgres.Client
+}
+
+// clusterResourceID returns the ID of the cluster (server) the client is connected to.
+func clusterResourceID(client *postgres.Client) *v2.ResourceId {
+	return &v2.ResourceId{
+		ResourceType: clusterResourceType.Id,
+		Resource:     formatClusterID(client.Host(), client.Port()),
+	}
+}
+
+// formatClusterID returns cluster:<host>/<port>. Colons in the host, as in IPv6 addresses, are replaced so the ID
+// has no colon after the resource type.
+func formatClusterID(host string, port uint16) string {
+	return fmt.Sprintf("%s:%s/%d", clusterResourceType.Id, strings.ReplaceAll(host, ":", "_"), port)
+}
+
+// parseClusterEntitlementID returns the role attribute slug of a cluster entitlement ID. Cluster IDs are parsed
+// separately from other entitlement IDs, whose database part starts with db, because the host may start with db too.
+func parseClusterEntitlementID(id string) (string, error) {
+	prefix := "entitlement:" + clusterResourceType.Id + ":"
+	if !strings.HasPrefix(id, prefix) {
+		return "", fmt.Errorf("baton-postgres: invalid cluster entitlement ID %s", id)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(id, prefix), ":")
+	if len(parts) != 2 && (len(parts) != 3 || parts[2] != "grant") {
+		return "", fmt.Errorf("baton-postgres: invalid cluster entitlement ID %s", id)
+	}
+
+	return parts[1], nil
+}
+
+func (r *clusterSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
+	return clusterResourceType
+}
+
+func (r *clusterSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID != nil {
+		return nil, "", nil, fmt.Errorf("unexpected parent resource ID on cluster")
+	}
+
+	var annos annotations.Annotations
+	annos.Append(&v2.ChildResourceType{ResourceTypeId: databaseResourceType.Id})
+
+	return []*v2.Resource{
+		{
+			DisplayName: fmt.Sprintf("%s:%d", r.client.Host(), r.client.Port()),
+			Id:          clusterResourceID(r.client),
+			Annotations: annos,
+		},
+	}, "", nil, nil
+}
+
+func (r *clusterSyncer) Entitlements(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var ens []*v2.Entitlement
+
+	ens = append(ens, &v2.Entitlement{
+		Resource:    resource,
+		Id:          formatEntitlementID(resource, "superuser", false),
+		DisplayName: "Superuser",
+		Description: "Has Superuser access",
+		GrantableTo: []*v2.ResourceType{roleResourceType},
+		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+		Slug:        "superuser",
+	})
+
+	ens = append(ens, &v2.Entitlement{
+		Resource:    resource,
+		Id:          formatEntitlementID(resource, "create-db", false),
+		DisplayName: "Create Database",
+		Description: "Can create new databases",
+		GrantableTo: []*v2.ResourceType{roleResourceType},
+		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+		Slug:        "create db",
+	})
+
+	ens = append(ens, &v2.Entitlement{
+		Resource:    resource,
+		Id:          formatEntitlementID(resource, "create-role", false),
+		DisplayName: "Create Role",
+		Description: "Can create new roles",
+		GrantableTo: []*v2.ResourceType{roleResourceType},
+		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+		Slug:        "create role",
+	})
+
+	ens = append(ens, &v2.Entitlement{
+		Resource:    resource,
+		Id:          formatEntitlementID(resource, "bypass-rls", false),
+		DisplayName: "Bypass RLS",
+		Description: "Can bypass row level security options",
+		GrantableTo: []*v2.ResourceType{roleResourceType},
+		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+		Slug:        "bypass rls",
+	})
+
+	ens = append(ens, &v2.Entitlement{
+		Resource:    resource,
+		Id:          formatEntitlementID(resource, "replication", false),
+		DisplayName: "Replication",
+		Description: "Can initiate replication connections, and create and drop replication slots",
+		GrantableTo: []*v2.ResourceType{roleResourceType},
+		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+		Slug:        "bypass rls",
+	})
+
+	return ens, "", nil, nil
+}
+
+func (r *clusterSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var ret []*v2.Grant
+
+	roles, nextPageToken, err := r.client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, r := range roles {
+		principal := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: roleResourceType.Id,
+				Resource:     formatObjectID(roleResourceType.Id, r.ID),
+			},
+		}
+
+		if r.Superuser {
+			eID := formatEntitlementID(resource, "superuser", false)
+			ret = append(ret, &v2.Grant{
+				Entitlement: &v2.Entitlement{
+					Id:       eID,
+					Resource: resource,
+				},
+				Principal: principal,
+				Id:        formatGrantID(eID, principal.Id),
+			})
+		}
+
+		if r.CreateDb {
+			eID := formatEntitlementID(resource, "create-db", false)
+			ret = append(ret, &v2.Grant{
+				Entitlement: &v2.Entitlement{
+					Id:       eID,
+					Resource: resource,
+				},
+				Principal: principal,
+				Id:        formatGrantID(eID, principal.Id),
+			})
+		}
+
+		if r.CreateRole {
+			eID := formatEntitlementID(resource, "create-role", false)
+			ret = append(ret, &v2.Grant{
+				Entitlement: &v2.Entitlement{
+					Id:       eID,
+					Resource: resource,
+				},
+				Principal: principal,
+				Id:        formatGrantID(eID, principal.Id),
+			})
+		}
+
+		if r.BypassRowSecurity {
+			eID := formatEntitlementID(resource, "bypass-rls", false)
+			ret = append(ret, &v2.Grant{
+				Entitlement: &v2.Entitlement{
+					Id:       eID,
+					Resource: resource,
+				},
+				Principal: principal,
+				Id:        formatGrantID(eID, principal.Id),
+			})
+		}
+
+		if r.Replication {
+			eID := formatEntitlementID(resource, "replication", false)
+			ret = append(ret, &v2.Grant{
+				Entitlement: &v2.Entitlement{
+					Id:       eID,
+					Resource: resource,
+				},
+				Principal: principal,
+				Id:        formatGrantID(eID, principal.Id),
+			})
+		}
+	}
+
+	return ret, nextPageToken, nil, nil
+}
+
+func (r *clusterSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-postgres: only users and roles can have role attributes granted")
+	}
+
+	privilegeName, err := parseClusterEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attribute, ok := roleAttributeEntitlements[privilegeName]
+	if !ok {
+		return nil, nil, fmt.Errorf("baton-postgres: unknown role attribute entitlement %s", entitlement.Id)
+	}
+
+	err = grantWithExpiry(ctx, r.client, principal, entitlement, func() error {
+		return r.client.AlterRoleAttribute(ctx, principal.DisplayName, attribute, true)
+	})
+	return nil, nil, err
+}
+
+func (r *clusterSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	entitlement := grant.Entitlement
+	principal := grant.Principal
+
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, fmt.Errorf("baton-postgres: only users and roles can have role attributes revoked")
+	}
+
+	privilegeName, err := parseClusterEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute, ok := roleAttributeEntitlements[privilegeName]
+	if !ok {
+		return nil, fmt.Errorf("baton-postgres: unknown role attribute entitlement %s", entitlement.Id)
+	}
+
+	err = r.client.AlterRoleAttribute(ctx, principal.DisplayName, attribute, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.client, grant)
+}
+
+func newClusterSyncer(ctx context.Context, c *postgres.Client) *clusterSyncer {
+	return &clusterSyncer{
+		resourceType: clusterResourceType,
+		client:       c,
+	}
+}
@@ -0,0 +1,36 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+func TestParseClusterEntitlementID(t *testing.T) {
+	for _, host := range []string{"localhost", "db.internal", "db-primary", "::1"} {
+		resource := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: clusterResourceType.Id,
+				Resource:     formatClusterID(host, 5432),
+			},
+		}
+
+		slug, err := parseClusterEntitlementID(formatEntitlementID(resource, "superuser", false))
+		require.NoError(t, err, host)
+		require.Equal(t, "superuser", slug, host)
+
+		slug, err = parseClusterEntitlementID(formatEntitlementID(resource, "create-role", true))
+		require.NoError(t, err, host)
+		require.Equal(t, "create-role", slug, host)
+
+		// The generic parser must not panic on cluster IDs whose host starts with db.
+		require.NotPanics(t, func() {
+			_, _, _, _, _ = parseEntitlementID(formatEntitlementID(resource, "superuser", false))
+		})
+	}
+
+	_, err := parseClusterEntitlementID("entitlement:table:db5:123:select")
+	require.Error(t, err)
+}
@@ -20,6 +20,7 @@ var columnResourceType = &v2.ResourceType{
 type columnSyncer struct {
 	resourceType *v2.ResourceType
 	clientPool   *postgres.ClientDatabasesPool
+	cache        *syncCache
 }
 
 func (r *columnSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -91,7 +92,7 @@ func (r *columnSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	graph, err := r.cache.roleGraph(ctx, client)
 	if err != nil {
 		return nil, "", nil, err
 	}
@@ -101,17 +102,85 @@ func (r *columnSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken
 		return nil, "", nil, err
 	}
 
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, col)
+	ret, err := roleGrantsForPrivileges(ctx, graph, resource, col)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	return ret, "", nil, nil
+}
+
+func (r *columnSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-postgres: only users and roles can have column granted")
+	}
+
+	db, tID, cID, privilegeName, isGrant, err := parseColumnEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbClient, _, err := r.clientPool.Get(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	col, err := dbClient.GetColumn(ctx, tID, cID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		return dbClient.GrantColumn(ctx, col.Schema, col.TableName, col.Name, principal.DisplayName, privilegeName, isGrant)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []*v2.Grant{
+		{
+			Id:          fmt.Sprintf("%s:%s:%s", entitlement.Id, principal.Id.ResourceType, principal.Id.Resource),
+			Entitlement: entitlement,
+			Principal:   principal,
+		},
+	}, nil, nil
+}
+
+func (r *columnSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	entitlement := grant.Entitlement
+	principal := grant.Principal
+
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, fmt.Errorf("baton-postgres: only users and roles can have column revoked")
+	}
+
+	db, tID, cID, privilegeName, isGrant, err := parseColumnEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbClient, _, err := r.clientPool.Get(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	col, err := dbClient.GetColumn(ctx, tID, cID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dbClient.RevokeColumn(ctx, col.Schema, col.TableName, col.Name, principal.DisplayName, privilegeName, isGrant)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newColumnSyncer(ctx context.Context, c *postgres.ClientDatabasesPool) *columnSyncer {
+func newColumnSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, cache *syncCache) *columnSyncer {
 	return &columnSyncer{
 		resourceType: columnResourceType,
 		clientPool:   c,
+		cache:        cache,
 	}
 }
@@ -4,6 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"regexp"
+	"text/template"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 
 	"github.com/conductorone/baton-postgresql/pkg/postgres"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -18,23 +23,37 @@ type Postgresql struct {
 	includeLargeObjects  bool
 	syncAllDatabases     bool
 	skipBuiltInFunctions bool
+	allowSchemaCascade   bool
+	schemaOwner          string
+	groupNoLoginRoles    bool
+	groupRolePattern     *regexp.Regexp
+	successorRole        string
+	loginNameTemplate    *template.Template
+	emails               *emailResolver
+	accountTypes         *accountTypeClassifier
+	cache                *syncCache
 }
 
 func (o *Postgresql) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
 	return []connectorbuilder.ResourceSyncer{
-		newRoleSyncer(ctx, o.clientPool.Default(ctx)),
-		newSchemaSyncer(ctx, o.clientPool),
-		newTableSyncer(ctx, o.clientPool, o.includeColumns),
-		newViewSyncer(ctx, o.clientPool),
-		newColumnSyncer(ctx, o.clientPool),
-		newFunctionSyncer(ctx, o.clientPool, o.skipBuiltInFunctions),
-		newProcedureSyncer(ctx, o.clientPool),
-		newLargeObjectSyncer(ctx, o.clientPool.Default(ctx), o.includeLargeObjects),
-		newDatabaseSyncer(ctx, o.clientPool, o.syncAllDatabases),
-		newSequenceSyncer(ctx, o.clientPool),
+		newClusterSyncer(ctx, o.clientPool.Default(ctx)),
+		newRoleSyncer(ctx, o.clientPool, o.groupNoLoginRoles, o.groupRolePattern, o.successorRole, o.loginNameTemplate, o.emails, o.accountTypes, o.cache),
+		newSchemaSyncer(ctx, o.clientPool, o.allowSchemaCascade, o.schemaOwner, o.cache),
+		newTableSyncer(ctx, o.clientPool, o.includeColumns, o.cache),
+		newViewSyncer(ctx, o.clientPool, o.cache),
+		newColumnSyncer(ctx, o.clientPool, o.cache),
+		newFunctionSyncer(ctx, o.clientPool, o.skipBuiltInFunctions, o.cache),
+		newProcedureSyncer(ctx, o.clientPool, o.cache),
+		newLargeObjectSyncer(ctx, o.clientPool, o.includeLargeObjects, o.cache),
+		newDatabaseSyncer(ctx, o.clientPool, o.syncAllDatabases, o.includeLargeObjects, o.cache),
+		newSequenceSyncer(ctx, o.clientPool, o.cache),
 	}
 }
 
+func (o *Postgresql) RegisterActionManager(ctx context.Context) (connectorbuilder.CustomActionManager, error) {
+	return newActionManager(ctx, o.clientPool), nil
+}
+
 func (c *Postgresql) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
 	var annos annotations.Annotations
 
@@ -49,12 +68,51 @@ func (c *Postgresql) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error
 					Description: "This email will be used as the login for the user.",
 					Field:       &v2.ConnectorAccountCreationSchema_Field_StringField{},
 				},
+				"groups": {
+					DisplayName: "Groups",
+					Required:    false,
+					Description: "Roles the user will be made a member of.",
+					Field:       &v2.ConnectorAccountCreationSchema_Field_StringListField{},
+				},
+				"connection_limit": {
+					DisplayName: "Connection limit",
+					Required:    false,
+					Description: "How many concurrent connections the user can make. -1 (the default) means no limit.",
+					Field:       &v2.ConnectorAccountCreationSchema_Field_IntField{},
+				},
+				"valid_until": {
+					DisplayName: "Valid until",
+					Required:    false,
+					Description: "Date (YYYY-MM-DD) or RFC 3339 time after which the user's password is no longer valid.",
+					Field:       &v2.ConnectorAccountCreationSchema_Field_StringField{},
+				},
+				"no_password": {
+					DisplayName: "No password",
+					Required:    false,
+					Description: "Create the user without a password, for users that authenticate with a certificate, LDAP or GSSAPI.",
+					Field:       &v2.ConnectorAccountCreationSchema_Field_BoolField{},
+				},
 			},
 		},
 	}, nil
 }
 
+// Validate runs at the start of every sync, which is also when expired time-bound grants are revoked and the
+// catalog data cached by the previous sync is dropped. A failed sweep is retried on the next sync rather than
+// failing this one.
 func (c *Postgresql) Validate(ctx context.Context) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	c.cache.reset()
+
+	revoked, err := c.SweepExpiredGrants(ctx)
+	if err != nil {
+		l.Error("error revoking expired grants", zap.Error(err))
+	}
+	if revoked > 0 {
+		l.Info("revoked expired grants", zap.Int("count", revoked))
+	}
+
 	return nil, nil
 }
 
@@ -70,8 +128,43 @@ func New(
 	includeLargeObjects bool,
 	syncAllDatabases bool,
 	skipBuiltInFunctions bool,
+	opts ...Option,
 ) (*Postgresql, error) {
-	clientPool, err := postgres.NewClientDatabasesPool(ctx, dsn, postgres.WithSchemaFilter(schemas))
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var groupRoleRegexp *regexp.Regexp
+	if o.groupRolePattern != "" {
+		var err error
+		groupRoleRegexp, err = regexp.Compile(o.groupRolePattern)
+		if err != nil {
+			return nil, fmt.Errorf("baton-postgres: invalid group role pattern: %w", err)
+		}
+	}
+
+	loginNameTmpl, err := parseLoginNameTemplate(o.loginNameTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	emails, err := newEmailResolver(o.emailSources, o.emailSecurityLabelProvider, o.emailLookupQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	accountTypes, err := newAccountTypeClassifier(o.accountTypeRules)
+	if err != nil {
+		return nil, err
+	}
+
+	clientPool, err := postgres.NewClientDatabasesPool(
+		ctx,
+		dsn,
+		postgres.WithSchemaFilter(schemas),
+		postgres.WithProtectedRoles(o.protectedRoles),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create postgres client pool: %w", err)
 	}
@@ -83,5 +176,14 @@ func New(
 		includeLargeObjects:  includeLargeObjects,
 		syncAllDatabases:     syncAllDatabases,
 		skipBuiltInFunctions: skipBuiltInFunctions,
+		allowSchemaCascade:   o.allowSchemaCascade,
+		schemaOwner:          o.schemaOwner,
+		groupNoLoginRoles:    o.groupNoLoginRoles,
+		groupRolePattern:     groupRoleRegexp,
+		successorRole:        o.successorRole,
+		loginNameTemplate:    loginNameTmpl,
+		emails:               emails,
+		accountTypes:         accountTypes,
+		cache:                newSyncCache(),
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/conductorone/baton-postgresql/pkg/testutil"
 	connectorV2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/dotc1z"
 	"github.com/conductorone/baton-sdk/pkg/dotc1z/manager"
@@ -43,7 +44,7 @@ type inMemoryConnectorClient struct {
 	connectorV2.ActionServiceClient
 }
 
-func newTestConnector(t *testing.T) (context.Context, sync.Syncer, manager.Manager, *inMemoryConnectorClient) {
+func newTestConnector(t *testing.T, opts ...Option) (context.Context, sync.Syncer, manager.Manager, *inMemoryConnectorClient) {
 	ctx := context.Background()
 
 	container := testutil.SetupPostgresContainer(ctx, t)
@@ -56,11 +57,13 @@ func newTestConnector(t *testing.T) (context.Context, sync.Syncer, manager.Manag
 		true,
 		true,
 		true,
+		opts...,
 	)
 	require.NoError(t, err)
 
 	srv, err := connectorbuilder.NewConnector(ctx, postgresConnector)
 	require.NoError(t, err)
+	srv = postgresConnector.ReleaseCacheAfterSync(srv)
 
 	tempPath, err := os.CreateTemp("", "baton-postgresql-test-c1z")
 	require.NoError(t, err)
@@ -184,3 +187,27 @@ func TestConnectorFullSync(t *testing.T) {
 	err = syncer.Close(ctx)
 	require.NoError(t, err)
 }
+
+func TestConnectorFullSyncGroupNoLoginRoles(t *testing.T) {
+	ctx, syncer, manager, _ := newTestConnector(t, WithGroupNoLoginRoles(true))
+
+	err := syncer.Sync(ctx)
+	require.NoError(t, err)
+	err = syncer.Close(ctx)
+	require.NoError(t, err)
+
+	c1z, err := manager.LoadC1Z(ctx)
+	require.NoError(t, err)
+	defer func(c1z *dotc1z.C1File) {
+		err := c1z.Close()
+		require.NoError(t, err)
+	}(c1z)
+
+	// pg_signal_backend is a NOLOGIN role without members, so only the option makes it a group.
+	roleResource, err := getByDisplayName(ctx, c1z, roleResourceType, "pg_signal_backend")
+	require.NoError(t, err)
+	require.NotNil(t, roleResource)
+
+	annos := annotations.Annotations(roleResource.Annotations)
+	require.True(t, annos.Contains(&connectorV2.GroupTrait{}))
+}
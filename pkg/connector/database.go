@@ -29,10 +29,12 @@ var databaseToSkip = map[string]bool{
 }
 
 type databaseSyncer struct {
-	resourceType     *v2.ResourceType
-	clientPool       *postgres.ClientDatabasesPool
-	client           *postgres.Client
-	syncAllDatabases bool
+	resourceType        *v2.ResourceType
+	clientPool          *postgres.ClientDatabasesPool
+	client              *postgres.Client
+	syncAllDatabases    bool
+	includeLargeObjects bool
+	cache               *syncCache
 }
 
 func (r *databaseSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -43,6 +45,9 @@ func (r *databaseSyncer) makeResource(ctx context.Context, dbModel *postgres.Dat
 	var annos annotations.Annotations
 
 	annos.Append(&v2.ChildResourceType{ResourceTypeId: schemaResourceType.Id})
+	if r.includeLargeObjects {
+		annos.Append(&v2.ChildResourceType{ResourceTypeId: largeObjectResourceType.Id})
+	}
 
 	return &v2.Resource{
 		DisplayName: dbModel.Name,
@@ -50,7 +55,8 @@ func (r *databaseSyncer) makeResource(ctx context.Context, dbModel *postgres.Dat
 			ResourceType: r.resourceType.Id,
 			Resource:     formatObjectID(r.resourceType.Id, dbModel.ID),
 		},
-		Annotations: annos,
+		ParentResourceId: clusterResourceID(r.client),
+		Annotations:      annos,
 	}
 }
 
@@ -58,8 +64,12 @@ func (r *databaseSyncer) List(ctx context.Context, parentResourceID *v2.Resource
 	l := ctxzap.Extract(ctx)
 	var err error
 
-	if parentResourceID != nil {
-		return nil, "", nil, fmt.Errorf("unexpected parent resource ID on database")
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	if parentResourceID.ResourceType != clusterResourceType.Id {
+		return nil, "", nil, fmt.Errorf("invalid parent resource ID on database")
 	}
 
 	databases, nextPageToken, err := r.clientPool.
@@ -111,55 +121,7 @@ func (r *databaseSyncer) Entitlements(ctx context.Context, resource *v2.Resource
 		return nil, "", nil, err
 	}
 
-	ens = append(ens, &v2.Entitlement{
-		Resource:    resource,
-		Id:          formatEntitlementID(resource, "superuser", false),
-		DisplayName: "Superuser",
-		Description: "Has Superuser access",
-		GrantableTo: []*v2.ResourceType{roleResourceType},
-		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
-		Slug:        "superuser",
-	})
-
-	ens = append(ens, &v2.Entitlement{
-		Resource:    resource,
-		Id:          formatEntitlementID(resource, "create-db", false),
-		DisplayName: "Create Database",
-		Description: "Can create new databases",
-		GrantableTo: []*v2.ResourceType{roleResourceType},
-		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
-		Slug:        "create db",
-	})
-
-	ens = append(ens, &v2.Entitlement{
-		Resource:    resource,
-		Id:          formatEntitlementID(resource, "create-role", false),
-		DisplayName: "Create Role",
-		Description: "Can create new roles",
-		GrantableTo: []*v2.ResourceType{roleResourceType},
-		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
-		Slug:        "create role",
-	})
-
-	ens = append(ens, &v2.Entitlement{
-		Resource:    resource,
-		Id:          formatEntitlementID(resource, "bypass-rls", false),
-		DisplayName: "Bypass RLS",
-		Description: "Can bypass row level security options",
-		GrantableTo: []*v2.ResourceType{roleResourceType},
-		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
-		Slug:        "bypass rls",
-	})
-
-	ens = append(ens, &v2.Entitlement{
-		Resource:    resource,
-		Id:          formatEntitlementID(resource, "replication", false),
-		DisplayName: "Replication",
-		Description: "Can initiate replication connections, and create and drop replication slots",
-		GrantableTo: []*v2.ResourceType{roleResourceType},
-		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
-		Slug:        "bypass rls",
-	})
+	ens = append(ens, ownerEntitlement(resource))
 
 	return ens, "", nil, nil
 }
@@ -175,86 +137,19 @@ func (r *databaseSyncer) Grants(ctx context.Context, resource *v2.Resource, pTok
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, err := r.client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	graph, err := r.cache.roleGraph(ctx, r.client)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	ret, err := roleGrantsForPrivileges(ctx, r.client, resource, roles, db)
+	ret, err := roleGrantsForPrivileges(ctx, graph, resource, db)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, r := range roles {
-		principal := &v2.Resource{
-			Id: &v2.ResourceId{
-				ResourceType: roleResourceType.Id,
-				Resource:     formatObjectID(roleResourceType.Id, r.ID),
-			},
-		}
-
-		if r.Superuser {
-			eID := formatEntitlementID(resource, "superuser", false)
-			ret = append(ret, &v2.Grant{
-				Entitlement: &v2.Entitlement{
-					Id:       eID,
-					Resource: resource,
-				},
-				Principal: principal,
-				Id:        formatGrantID(eID, principal.Id),
-			})
-		}
-
-		if r.CreateDb {
-			eID := formatEntitlementID(resource, "create-db", false)
-			ret = append(ret, &v2.Grant{
-				Entitlement: &v2.Entitlement{
-					Id:       eID,
-					Resource: resource,
-				},
-				Principal: principal,
-				Id:        formatGrantID(eID, principal.Id),
-			})
-		}
-
-		if r.CreateRole {
-			eID := formatEntitlementID(resource, "create-role", false)
-			ret = append(ret, &v2.Grant{
-				Entitlement: &v2.Entitlement{
-					Id:       eID,
-					Resource: resource,
-				},
-				Principal: principal,
-				Id:        formatGrantID(eID, principal.Id),
-			})
-		}
+	ret = append(ret, ownerGrant(resource, db))
 
-		if r.BypassRowSecurity {
-			eID := formatEntitlementID(resource, "bypass-rls", false)
-			ret = append(ret, &v2.Grant{
-				Entitlement: &v2.Entitlement{
-					Id:       eID,
-					Resource: resource,
-				},
-				Principal: principal,
-				Id:        formatGrantID(eID, principal.Id),
-			})
-		}
-
-		if r.Replication {
-			eID := formatEntitlementID(resource, "replication", false)
-			ret = append(ret, &v2.Grant{
-				Entitlement: &v2.Entitlement{
-					Id:       eID,
-					Resource: resource,
-				},
-				Principal: principal,
-				Id:        formatGrantID(eID, principal.Id),
-			})
-		}
-	}
-
-	return ret, nextPageToken, nil, nil
+	return ret, "", nil, nil
 }
 
 func (r *databaseSyncer) Create(ctx context.Context, resource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
@@ -313,7 +208,12 @@ func (r *databaseSyncer) Grant(ctx context.Context, principal *v2.Resource, enti
 	}
 
 	principalName := principal.DisplayName
-	err = r.client.GrantDatabase(ctx, pgDb.Name, principalName, privilegeName, isGrant)
+	err = grantWithExpiry(ctx, r.client, principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return r.client.SetDatabaseOwner(ctx, pgDb.Name, principalName)
+		}
+		return r.client.GrantDatabase(ctx, pgDb.Name, principalName, privilegeName, isGrant)
+	})
 	return nil, nil, err
 }
 
@@ -326,6 +226,10 @@ func (r *databaseSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 		return nil, err
 	}
 
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
 	dbID, err := strconv.ParseInt(dbIdStr, 10, 64)
 	if err != nil {
 		return nil, err
@@ -338,14 +242,20 @@ func (r *databaseSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 
 	principalName := principal.DisplayName
 	err = r.client.RevokeDatabase(ctx, pgDb.Name, principalName, privilegeName, isGrant)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.client, grant)
 }
 
-func newDatabaseSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, syncAllDatabases bool) *databaseSyncer {
+func newDatabaseSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, syncAllDatabases bool, includeLargeObjects bool, cache *syncCache) *databaseSyncer {
 	return &databaseSyncer{
-		resourceType:     databaseResourceType,
-		clientPool:       c,
-		client:           c.Default(ctx),
-		syncAllDatabases: syncAllDatabases,
+		resourceType:        databaseResourceType,
+		clientPool:          c,
+		client:              c.Default(ctx),
+		syncAllDatabases:    syncAllDatabases,
+		includeLargeObjects: includeLargeObjects,
+		cache:               cache,
 	}
 }
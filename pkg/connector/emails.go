@@ -0,0 +1,146 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+)
+
+// Sources of a role's email, tried in the configured order until one yields an address.
+const (
+	// emailSourceName uses the role name when it is an email, as roles provisioned with the default login name are.
+	emailSourceName = "name"
+	// emailSourceComment finds an email in the role's COMMENT.
+	emailSourceComment = "comment"
+	// emailSourceSecurityLabel finds an email in a SECURITY LABEL on the role.
+	emailSourceSecurityLabel = "security-label"
+	// emailSourceQuery looks the email up with a user-supplied query in the default database.
+	emailSourceQuery = "query"
+)
+
+var defaultEmailSources = []string{emailSourceName, emailSourceComment}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+type emailResolver struct {
+	sources       []string
+	labelProvider string
+	lookupQuery   string
+}
+
+func newEmailResolver(sources []string, labelProvider string, lookupQuery string) (*emailResolver, error) {
+	if len(sources) == 0 {
+		sources = defaultEmailSources
+	}
+
+	for _, source := range sources {
+		switch source {
+		case emailSourceName, emailSourceComment, emailSourceSecurityLabel:
+		case emailSourceQuery:
+			if lookupQuery == "" {
+				return nil, fmt.Errorf("baton-postgres: the %s email source needs an email lookup query", emailSourceQuery)
+			}
+		default:
+			return nil, fmt.Errorf("baton-postgres: unknown email source %q", source)
+		}
+	}
+
+	return &emailResolver{
+		sources:       sources,
+		labelProvider: labelProvider,
+		lookupQuery:   lookupQuery,
+	}, nil
+}
+
+func (e *emailResolver) usesSource(source string) bool {
+	for _, s := range e.sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup runs the lookup query for a page of roles. It returns nil when the query source isn't used.
+func (e *emailResolver) lookup(ctx context.Context, client *postgres.Client, roles []*postgres.RoleModel) (map[string]string, error) {
+	if !e.usesSource(emailSourceQuery) || len(roles) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+
+	return client.LookupRoleEmails(ctx, e.lookupQuery, names)
+}
+
+// resolve returns the email of roleModel from the first source that has one, or "" if none does. lookedUp is the
+// result of lookup for the page the role is in.
+func (e *emailResolver) resolve(roleModel *postgres.RoleModel, lookedUp map[string]string) string {
+	for _, source := range e.sources {
+		var email string
+		switch source {
+		case emailSourceName:
+			if strings.Contains(roleModel.Name, "@") {
+				email = roleModel.Name
+			}
+		case emailSourceComment:
+			if roleModel.Comment != nil {
+				email = findEmail(*roleModel.Comment)
+			}
+		case emailSourceSecurityLabel:
+			email = e.labelEmail(roleModel.SecurityLabels)
+		case emailSourceQuery:
+			email = findEmail(lookedUp[roleModel.Name])
+		}
+
+		if email != "" {
+			return email
+		}
+	}
+
+	return ""
+}
+
+// labelEmail finds an email in the label set by the configured provider or, without one, in the labels of every
+// provider in name order.
+func (e *emailResolver) labelEmail(labels map[string]string) string {
+	if e.labelProvider != "" {
+		return findEmail(labels[e.labelProvider])
+	}
+
+	providers := make([]string, 0, len(labels))
+	for provider := range labels {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	for _, provider := range providers {
+		if email := findEmail(labels[provider]); email != "" {
+			return email
+		}
+	}
+
+	return ""
+}
+
+// findEmail returns the first email address in free-form text such as "Jane Doe <jane.doe@corp.com>".
+func findEmail(text string) string {
+	candidate := emailPattern.FindString(text)
+	if candidate == "" {
+		return ""
+	}
+
+	addr, err := mail.ParseAddress(candidate)
+	if err != nil {
+		return ""
+	}
+
+	return addr.Address
+}
@@ -0,0 +1,44 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+)
+
+func TestEmailResolver(t *testing.T) {
+	comment := "Reporting service, owned by Jane Doe <jane.doe@corp.com>"
+	role := &postgres.RoleModel{
+		Name:    "jdoe",
+		Comment: &comment,
+		SecurityLabels: map[string]string{
+			"ldap": "uid=jdoe,mail=jdoe@ldap.corp.com",
+		},
+	}
+
+	e, err := newEmailResolver(nil, "", "")
+	require.NoError(t, err)
+	require.Equal(t, "jane.doe@corp.com", e.resolve(role, nil))
+	require.Equal(t, "jane@corp.com", e.resolve(&postgres.RoleModel{Name: "jane@corp.com"}, nil))
+
+	e, err = newEmailResolver([]string{emailSourceSecurityLabel, emailSourceComment}, "", "")
+	require.NoError(t, err)
+	require.Equal(t, "jdoe@ldap.corp.com", e.resolve(role, nil))
+
+	e, err = newEmailResolver([]string{emailSourceSecurityLabel}, "selinux", "")
+	require.NoError(t, err)
+	require.Empty(t, e.resolve(role, nil))
+
+	e, err = newEmailResolver([]string{emailSourceQuery, emailSourceComment}, "", "SELECT login, email FROM people WHERE login = ANY($1)")
+	require.NoError(t, err)
+	require.Equal(t, "john@corp.com", e.resolve(role, map[string]string{"jdoe": "john@corp.com"}))
+	require.Equal(t, "jane.doe@corp.com", e.resolve(role, nil))
+
+	_, err = newEmailResolver([]string{emailSourceQuery}, "", "")
+	require.Error(t, err)
+
+	_, err = newEmailResolver([]string{"ldap"}, "", "")
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ type functionSyncer struct {
 	resourceType         *v2.ResourceType
 	clientPool           *postgres.ClientDatabasesPool
 	skipBuiltInFunctions bool
+	cache                *syncCache
 }
 
 func (r *functionSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -90,6 +91,8 @@ func (r *functionSyncer) Entitlements(ctx context.Context, resource *v2.Resource
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	for _, en := range ens {
 		en.DisplayName = fmt.Sprintf("%s on %s", dbModel.Name, en.DisplayName)
 	}
@@ -113,17 +116,19 @@ func (r *functionSyncer) Grants(ctx context.Context, resource *v2.Resource, pTok
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	graph, err := r.cache.roleGraph(ctx, client)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, function)
+	ret, err := roleGrantsForPrivileges(ctx, graph, resource, function)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	ret = append(ret, ownerGrant(resource, function))
+
+	return ret, "", nil, nil
 }
 
 func (r *functionSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
@@ -151,10 +156,16 @@ func (r *functionSyncer) Grant(ctx context.Context, principal *v2.Resource, enti
 		return nil, nil, err
 	}
 
-	err = dbClient.GrantFunction(ctx, function.Schema, function, principal.DisplayName, privilegeName, isGrant)
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetFunctionOwner(ctx, function.Schema, function, principal.DisplayName)
+		}
+		return dbClient.GrantFunction(ctx, function.Schema, function, principal.DisplayName, privilegeName, isGrant)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
+
 	return []*v2.Grant{
 		{
 			Id:          fmt.Sprintf("%s:%s:%s", entitlement.Id, principal.Id.ResourceType, principal.Id.Resource),
@@ -177,6 +188,10 @@ func (r *functionSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 		return nil, err
 	}
 
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
 	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
 	if err != nil {
 		return nil, err
@@ -193,13 +208,18 @@ func (r *functionSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 	}
 
 	err = dbClient.RevokeFunction(ctx, function.Schema, function, principal.DisplayName, privilegeName, isGrant)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newFunctionSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, skipBuiltInFunctions bool) *functionSyncer {
+func newFunctionSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, skipBuiltInFunctions bool, cache *syncCache) *functionSyncer {
 	return &functionSyncer{
 		resourceType:         functionResourceType,
 		clientPool:           c,
 		skipBuiltInFunctions: skipBuiltInFunctions,
+		cache:                cache,
 	}
 }
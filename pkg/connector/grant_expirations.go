@@ -0,0 +1,217 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+)
+
+// grantDuration returns how long a grant of entitlement should last. The baton SDK has no field for this, so the
+// caller of the Grant RPC supplies it as a google.protobuf.Duration annotation on the entitlement in the request, e.g.
+// by adding durationpb.New(24*time.Hour) to Entitlement.Annotations. Grants requested without one don't expire.
+func grantDuration(entitlement *v2.Entitlement) (time.Duration, bool, error) {
+	annos := annotations.Annotations(entitlement.Annotations)
+	d := &durationpb.Duration{}
+	ok, err := annos.Pick(d)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	if err := d.CheckValid(); err != nil {
+		return 0, false, fmt.Errorf("baton-postgres: invalid grant duration: %w", err)
+	}
+
+	duration := d.AsDuration()
+	if duration <= 0 {
+		return 0, false, fmt.Errorf("baton-postgres: grant duration must be positive, got %s", duration)
+	}
+
+	return duration, true, nil
+}
+
+// grantWithExpiry makes a grant of entitlement to principal with grant, recording its expiry first. The request is
+// validated before anything is changed, and the expiry is recorded before the grant is made so that a time-bound
+// grant never exists without a record of when to revoke it. If the grant then fails, the previous record is restored.
+// A grant without a duration replaces a time-bound grant of the same entitlement, so any expiry recorded earlier is
+// forgotten.
+func grantWithExpiry(
+	ctx context.Context,
+	client *postgres.Client,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+	grant func() error,
+) error {
+	duration, ok, err := grantDuration(entitlement)
+	if err != nil {
+		return err
+	}
+
+	if ok && strings.HasSuffix(entitlement.Id, ":"+ownerSlug) {
+		return fmt.Errorf("baton-postgres: ownership cannot be granted for a limited time")
+	}
+
+	previous, err := client.GetGrantExpiration(ctx, entitlement.Id, principal.Id.Resource)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		err = client.SetGrantExpiration(ctx, &postgres.GrantExpirationModel{
+			EntitlementID: entitlement.Id,
+			ResourceType:  entitlement.Resource.Id.ResourceType,
+			ResourceID:    entitlement.Resource.Id.Resource,
+			PrincipalType: principal.Id.ResourceType,
+			PrincipalID:   principal.Id.Resource,
+			PrincipalName: principal.DisplayName,
+			ExpiresAt:     time.Now().Add(duration),
+		})
+	} else if previous != nil {
+		err = client.DeleteGrantExpiration(ctx, entitlement.Id, principal.Id.Resource)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = grant()
+	if err == nil {
+		return nil
+	}
+
+	var restoreErr error
+	switch {
+	case previous != nil:
+		restoreErr = client.SetGrantExpiration(ctx, previous)
+	case ok:
+		restoreErr = client.DeleteGrantExpiration(ctx, entitlement.Id, principal.Id.Resource)
+	}
+	if restoreErr != nil {
+		ctxzap.Extract(ctx).Warn(
+			"error restoring grant expiration after a failed grant",
+			zap.String("entitlement_id", entitlement.Id),
+			zap.String("principal_id", principal.Id.Resource),
+			zap.Error(restoreErr),
+		)
+		return errors.Join(err, restoreErr)
+	}
+
+	return err
+}
+
+// forgetGrantExpiry drops the recorded expiry of a grant that was revoked.
+func forgetGrantExpiry(ctx context.Context, client *postgres.Client, grant *v2.Grant) error {
+	return client.DeleteGrantExpiration(ctx, grant.Entitlement.Id, grant.Principal.Id.Resource)
+}
+
+// SweepExpiredGrants revokes every time-bound grant whose expiry has passed. Revoking a grant also forgets its
+// expiry, so a grant that fails to be revoked is retried on the next sweep. It returns the number of revoked grants.
+func (o *Postgresql) SweepExpiredGrants(ctx context.Context) (int, error) {
+	l := ctxzap.Extract(ctx)
+
+	client := o.clientPool.Default(ctx)
+	expired, err := client.ListExpiredGrants(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	provisioners := make(map[string]connectorbuilder.ResourceProvisionerV2)
+	for _, rs := range o.ResourceSyncers(ctx) {
+		if p, ok := rs.(connectorbuilder.ResourceProvisionerV2); ok {
+			provisioners[rs.ResourceType(ctx).Id] = p
+		}
+	}
+
+	var sweepErr error
+	revoked := 0
+	for _, e := range expired {
+		p, ok := provisioners[e.ResourceType]
+		if !ok {
+			sweepErr = errors.Join(sweepErr, fmt.Errorf("baton-postgres: no provisioner for expired grant on %s", e.ResourceType))
+			continue
+		}
+
+		// The principal may have been renamed or dropped since the grant was made, so look it up by ID rather than
+		// trusting the name recorded back then.
+		principalID, err := parseObjectID(e.PrincipalID)
+		if err != nil {
+			sweepErr = errors.Join(sweepErr, err)
+			continue
+		}
+		principal, err := client.GetRole(ctx, principalID)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				sweepErr = errors.Join(sweepErr, err)
+				continue
+			}
+
+			// A dropped role took its grants with it, so only the expiry is left to forget.
+			l.Info(
+				"forgetting expired grant of a role that no longer exists",
+				zap.String("entitlement_id", e.EntitlementID),
+				zap.String("principal_id", e.PrincipalID),
+				zap.String("principal", e.PrincipalName),
+			)
+			err = client.DeleteGrantExpiration(ctx, e.EntitlementID, e.PrincipalID)
+			if err != nil {
+				sweepErr = errors.Join(sweepErr, err)
+			}
+			continue
+		}
+
+		resource := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: e.ResourceType,
+				Resource:     e.ResourceID,
+			},
+		}
+		_, err = p.Revoke(ctx, &v2.Grant{
+			Id: formatGrantID(e.EntitlementID, &v2.ResourceId{ResourceType: e.PrincipalType, Resource: e.PrincipalID}),
+			Entitlement: &v2.Entitlement{
+				Id:       e.EntitlementID,
+				Resource: resource,
+			},
+			Principal: &v2.Resource{
+				Id: &v2.ResourceId{
+					ResourceType: e.PrincipalType,
+					Resource:     e.PrincipalID,
+				},
+				DisplayName: principal.Name,
+			},
+		})
+		if err != nil {
+			l.Warn(
+				"error revoking expired grant",
+				zap.String("entitlement_id", e.EntitlementID),
+				zap.String("principal_id", e.PrincipalID),
+				zap.Error(err),
+			)
+			sweepErr = errors.Join(sweepErr, err)
+			continue
+		}
+
+		l.Info(
+			"revoked expired grant",
+			zap.String("entitlement_id", e.EntitlementID),
+			zap.String("principal", principal.Name),
+			zap.Time("expired_at", e.ExpiresAt),
+		)
+		revoked++
+	}
+
+	return revoked, sweepErr
+}
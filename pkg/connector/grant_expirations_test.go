@@ -0,0 +1,45 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+func TestGrantDuration(t *testing.T) {
+	_, ok, err := grantDuration(&v2.Entitlement{Id: "entitlement:role:role:10:member"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	var annos annotations.Annotations
+	annos.Update(durationpb.New(2 * time.Hour))
+	duration, ok, err := grantDuration(&v2.Entitlement{Id: "entitlement:role:role:10:member", Annotations: annos})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Hour, duration)
+
+	annos = nil
+	annos.Update(durationpb.New(-time.Minute))
+	_, _, err = grantDuration(&v2.Entitlement{Id: "entitlement:role:role:10:member", Annotations: annos})
+	require.Error(t, err)
+}
+
+func TestGrantWithExpiryRejectsTimeBoundOwnership(t *testing.T) {
+	var annos annotations.Annotations
+	annos.Update(durationpb.New(time.Hour))
+	entitlement := &v2.Entitlement{Id: "entitlement:table:db5:123:owner", Annotations: annos}
+	principal := &v2.Resource{Id: &v2.ResourceId{ResourceType: roleResourceType.Id, Resource: "role:10"}}
+
+	// The request is rejected before the client is used or the grant is made.
+	err := grantWithExpiry(context.Background(), nil, principal, entitlement, func() error {
+		require.FailNow(t, "ownership was transferred")
+		return nil
+	})
+	require.Error(t, err)
+}
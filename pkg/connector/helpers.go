@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/conductorone/baton-postgresql/pkg/postgres"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
 )
 
+const ownerSlug = "owner"
+
+var errRevokeOwnership = errors.New("baton-postgres: ownership cannot be revoked, grant it to another role instead")
+
 func formatWithDatabaseID(resourceTypeID string, dbId string, id int64) string {
 	return fmt.Sprintf("%s:db%s:%d", resourceTypeID, dbId, id)
 }
@@ -72,6 +78,36 @@ func parseColumnID(id string) (string, int64, int64, error) {
 	return db, tID, colID, nil
 }
 
+// parseColumnEntitlementID parses a column entitlement ID and returns the
+// databaseId, tableId, columnId, privilegeName, isGrant flag, and an error if any.
+func parseColumnEntitlementID(id string) (string, int64, int64, string, bool, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] != "entitlement" {
+		return "", 0, 0, "", false, fmt.Errorf("invalid column entitlement ID: %s", id)
+	}
+
+	// The column ID is made of 4 parts, followed by the privilege name and an optional grant suffix.
+	idParts := strings.Split(parts[1], ":")
+	if len(idParts) != 5 && len(idParts) != 6 {
+		return "", 0, 0, "", false, fmt.Errorf("invalid column entitlement ID: %s", id)
+	}
+
+	db, tID, colID, err := parseColumnID(strings.Join(idParts[:4], ":"))
+	if err != nil {
+		return "", 0, 0, "", false, err
+	}
+
+	isGrant := false
+	if len(idParts) == 6 {
+		if idParts[5] != "grant" {
+			return "", 0, 0, "", false, fmt.Errorf("invalid column entitlement ID: %s", id)
+		}
+		isGrant = true
+	}
+
+	return db, tID, colID, idParts[4], isGrant, nil
+}
+
 func formatGrantID(entitlementID string, principalId *v2.ResourceId) string {
 	return fmt.Sprintf(
 		"grant:%s:%s",
@@ -104,6 +140,9 @@ func parseEntitlementID(id string) (string, string, string, bool, error) {
 	}
 
 	if strings.HasPrefix(parts[2], "db") {
+		if len(parts) < 5 {
+			return "", "", "", false, fmt.Errorf("invalid entitlement ID: %s", id)
+		}
 		return parts[1], fmt.Sprintf("%s:%s", parts[2], parts[3]), parts[4], isGrant, nil
 	}
 
@@ -150,113 +189,174 @@ func grantsForPrivilegeSet(
 	return ret, nil
 }
 
-func getInheritedACLs(
-	ctx context.Context,
-	client *postgres.Client,
-	role *postgres.RoleModel,
-	aclsByRole map[string][]*postgres.ACL,
-) ([]*postgres.ACL, error) {
-	var ret []*postgres.ACL
-
-	if !role.Inherit {
-		return nil, nil
+// roleExpansion makes a grant to a role apply to the role's members as well. Grants to a role expand through its
+// inherit entitlement, which only the members that inherit the role's privileges hold, and grants to PUBLIC through
+// its member entitlement, which every role holds. It returns nil for roles without members.
+func roleExpansion(graph *postgres.RoleGraph, role *v2.Resource, roleID int64) *v2.GrantExpandable {
+	if roleID == publicRoleID {
+		return &v2.GrantExpandable{
+			EntitlementIds: []string{formatEntitlementID(role, roleMemberSlug, false)},
+		}
 	}
 
-	for _, pID := range role.MemberOf {
-		pRole, err := client.GetRole(ctx, pID)
-		if err != nil {
-			return nil, err
-		}
-		ret = append(ret, aclsByRole[pRole.Name]...)
+	if !graph.HasMembers(roleID) {
+		return nil
+	}
 
-		parentACLs, err := getInheritedACLs(ctx, client, pRole, aclsByRole)
-		if err != nil {
-			return nil, err
-		}
+	return &v2.GrantExpandable{
+		EntitlementIds: []string{formatEntitlementID(role, roleInheritSlug, false)},
+	}
+}
 
-		ret = append(ret, parentACLs...)
+// roleGrantsForPrivileges returns the grants of the privileges on aclObj. Only the roles named in its ACL, its owner
+// and superusers get grants. Members of those roles get the same privileges by expanding the grants over role
+// membership. Privileges granted to PUBLIC, or by default when the object has no ACL, are granted once to the PUBLIC
+// role and expanded to every role.
+func roleGrantsForPrivileges(
+	ctx context.Context,
+	graph *postgres.RoleGraph,
+	resource *v2.Resource,
+	aclObj postgres.ACLResource,
+) ([]*v2.Grant, error) {
+	acls, err := postgres.DecodeACL(aclObj)
+	if err != nil {
+		return nil, err
 	}
 
-	return ret, nil
+	return roleGrantsForACLs(ctx, graph, resource, aclObj, acls)
 }
 
-func roleGrantsForPrivileges(
+// roleGrantsForACLs is roleGrantsForPrivileges with the ACL of aclObj already decoded.
+func roleGrantsForACLs(
 	ctx context.Context,
-	client *postgres.Client,
+	graph *postgres.RoleGraph,
 	resource *v2.Resource,
-	roles []*postgres.RoleModel,
 	aclObj postgres.ACLResource,
+	acls []*postgres.ACL,
 ) ([]*v2.Grant, error) {
-	var ret []*v2.Grant
+	privsByRole := make(map[int64]postgres.PrivilegeSet)
+	grantPrivsByRole := make(map[int64]postgres.PrivilegeSet)
 
-	aclsByRole := make(map[string][]*postgres.ACL)
+	// A NULL ACL stands for the default privileges, which PUBLIC holds besides the owner.
+	if aclObj.GetACLs() == nil && aclObj.DefaultPrivileges() != postgres.EmptyPrivilegeSet {
+		privsByRole[publicRoleID] = aclObj.DefaultPrivileges()
+	}
 
-	var defaultACL *postgres.ACL
-	for _, pgACL := range aclObj.GetACLs() {
-		acl, err := postgres.NewACL(pgACL)
-		if err != nil {
-			return nil, err
+	for _, acl := range acls {
+		id := int64(publicRoleID)
+		if !acl.IsPublic() {
+			role := aclGrantee(graph, acl)
+			if role == nil {
+				continue
+			}
+			id = role.ID
 		}
 
-		grantee := acl.Grantee()
-		if grantee == "" {
-			defaultACL = acl
-			continue
-		}
+		privsByRole[id] |= acl.Privileges()
+		grantPrivsByRole[id] |= acl.GrantPrivileges()
+	}
 
-		roleACLs, ok := aclsByRole[grantee]
-		if ok {
-			aclsByRole[grantee] = append(roleACLs, acl)
-		} else {
-			aclsByRole[grantee] = []*postgres.ACL{acl}
+	// Superusers and the owner of the object get all privileges.
+	for _, r := range graph.Roles() {
+		if r.Superuser || r.ID == aclObj.GetOwnerID() {
+			privsByRole[r.ID] = aclObj.AllPrivileges()
+			grantPrivsByRole[r.ID] = aclObj.AllPrivileges()
 		}
 	}
 
-	if defaultACL == nil {
-		defaultACL = postgres.NewACLFromPrivilegeSets(aclObj.DefaultPrivileges(), postgres.EmptyPrivilegeSet)
+	roleIDs := make([]int64, 0, len(privsByRole))
+	for id := range privsByRole {
+		roleIDs = append(roleIDs, id)
 	}
+	sort.Slice(roleIDs, func(i, j int) bool { return roleIDs[i] < roleIDs[j] })
 
-	for _, r := range roles {
-		privs := defaultACL.Privileges()
-		grantPrivs := defaultACL.GrantPrivileges()
-
-		roleACLs := aclsByRole[r.Name]
+	var ret []*v2.Grant
+	for _, id := range roleIDs {
+		principal := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: roleResourceType.Id,
+				Resource:     formatObjectID(roleResourceType.Id, id),
+			},
+		}
 
-		inheritedACLs, err := getInheritedACLs(ctx, client, r, aclsByRole)
+		grants, err := grantsForPrivilegeSet(ctx, resource, principal, privsByRole[id], grantPrivsByRole[id])
 		if err != nil {
 			return nil, err
 		}
 
-		roleACLs = append(roleACLs, inheritedACLs...)
-
-		// If the role is a super user or the owner of the object, they get all privileges
-		if r.Superuser || r.ID == aclObj.GetOwnerID() {
-			privs = aclObj.AllPrivileges()
-			grantPrivs = aclObj.AllPrivileges()
+		var annos annotations.Annotations
+		if expansion := roleExpansion(graph, principal, id); expansion != nil {
+			annos.Update(expansion)
 		}
-
-		// Set the ACL privs appropriately
-		for _, ra := range roleACLs {
-			privs |= ra.Privileges()
-			grantPrivs |= ra.GrantPrivileges()
+		// Grants to PUBLIC can't be revoked through the PUBLIC role, which isn't a real role.
+		if id == publicRoleID {
+			annos.Update(&v2.GrantImmutable{})
+		}
+		for _, g := range grants {
+			g.Annotations = annos
 		}
 
-		principal := &v2.Resource{
-			Id: &v2.ResourceId{
-				ResourceType: roleResourceType.Id,
-				Resource:     formatObjectID(roleResourceType.Id, r.ID),
-			},
+		ret = append(ret, grants...)
+	}
+
+	return ret, nil
+}
+
+// aclGrantee returns the role an ACL entry grants privileges to, or nil for roles that aren't in graph.
+func aclGrantee(graph *postgres.RoleGraph, acl *postgres.ACL) *postgres.RoleModel {
+	if id, ok := acl.GranteeID(); ok {
+		return graph.Role(id)
+	}
+
+	return graph.RoleByName(acl.Grantee())
+}
+
+// classGrants returns the grants on a table, view or sequence. During a sync the resource has its schema as parent,
+// so the ACLs of the whole schema are loaded at once and the grants of its other classes are answered from memory.
+// Otherwise, or if the class isn't in the loaded schema, get loads the class on its own.
+func classGrants(
+	ctx context.Context,
+	cache *syncCache,
+	client *postgres.Client,
+	resource *v2.Resource,
+	classID int64,
+	get func() (postgres.ACLResource, error),
+) ([]*v2.Grant, error) {
+	graph, err := cache.roleGraph(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var class *postgres.ClassACLModel
+	if parent := resource.GetParentResourceId(); parent.GetResourceType() == schemaResourceType.Id {
+		_, schemaID, err := parseWithDatabaseID(parent.GetResource())
+		if err != nil {
+			return nil, err
 		}
 
-		grants, err := grantsForPrivilegeSet(ctx, resource, principal, privs, grantPrivs)
+		class, err = cache.schemaClass(ctx, client, schemaID, classID)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		ret = append(ret, grants...)
+	var aclObj postgres.ACLResource
+	var ret []*v2.Grant
+	if class != nil {
+		aclObj = class.Object()
+		ret, err = roleGrantsForACLs(ctx, graph, resource, aclObj, class.DecodedACLs())
+	} else {
+		aclObj, err = get()
+		if err != nil {
+			return nil, err
+		}
+		ret, err = roleGrantsForPrivileges(ctx, graph, resource, aclObj)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return ret, nil
+	return append(ret, ownerGrant(resource, aclObj)), nil
 }
 
 func entitlementsForPrivs(ctx context.Context, resource *v2.Resource, privs postgres.PrivilegeSet) ([]*v2.Entitlement, error) {
@@ -293,3 +393,34 @@ func entitlementsForPrivs(ctx context.Context, resource *v2.Resource, privs post
 
 	return ret, nil
 }
+
+func ownerEntitlement(resource *v2.Resource) *v2.Entitlement {
+	return &v2.Entitlement{
+		Resource:    resource,
+		Id:          formatEntitlementID(resource, ownerSlug, false),
+		DisplayName: "Owner",
+		Description: fmt.Sprintf("Owns %s", resource.DisplayName),
+		GrantableTo: []*v2.ResourceType{roleResourceType},
+		Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+		Slug:        ownerSlug,
+	}
+}
+
+func ownerGrant(resource *v2.Resource, aclObj postgres.ACLResource) *v2.Grant {
+	eID := formatEntitlementID(resource, ownerSlug, false)
+	principal := &v2.Resource{
+		Id: &v2.ResourceId{
+			ResourceType: roleResourceType.Id,
+			Resource:     formatObjectID(roleResourceType.Id, aclObj.GetOwnerID()),
+		},
+	}
+
+	return &v2.Grant{
+		Entitlement: &v2.Entitlement{
+			Id:       eID,
+			Resource: resource,
+		},
+		Principal: principal,
+		Id:        formatGrantID(eID, principal.Id),
+	}
+}
@@ -0,0 +1,155 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+func TestRoleGrantsForACLs(t *testing.T) {
+	ctx := context.Background()
+
+	graph := postgres.NewRoleGraph([]*postgres.RoleModel{
+		{ID: 10, Name: "postgres", Superuser: true},
+		{ID: 20, Name: "owner"},
+		{ID: 30, Name: "readers"},
+		{ID: 40, Name: "alice", MemberOf: []int64{30}, InheritFrom: []int64{30}},
+	}, true)
+
+	resource := &v2.Resource{
+		Id: &v2.ResourceId{
+			ResourceType: tableResourceType.Id,
+			Resource:     formatObjectID(tableResourceType.Id, 100),
+		},
+		DisplayName: "orders",
+	}
+	table := &postgres.TableModel{
+		ID:      100,
+		Name:    "orders",
+		OwnerID: 20,
+		ACLs:    []string{"owner=arwdDxt/owner", "readers=r/owner", "=r/owner", "ghost=w/owner"},
+	}
+
+	var acls []*postgres.ACL
+	for _, pgACL := range table.ACLs {
+		acl, err := postgres.NewACL(pgACL)
+		require.NoError(t, err)
+		acls = append(acls, acl)
+	}
+
+	grants, err := roleGrantsForACLs(ctx, graph, resource, table, acls)
+	require.NoError(t, err)
+
+	byRole := make(map[string][]*v2.Grant)
+	for _, g := range grants {
+		byRole[g.Principal.Id.Resource] = append(byRole[g.Principal.Id.Resource], g)
+	}
+
+	// The superuser and the owner get every privilege, with and without grant option.
+	allPrivs := len(entitlementsForAllPrivs(t, resource, table.AllPrivileges()))
+	require.Len(t, byRole[formatObjectID(roleResourceType.Id, 10)], allPrivs)
+	require.Len(t, byRole[formatObjectID(roleResourceType.Id, 20)], allPrivs)
+
+	// Members of readers get SELECT through expansion rather than a grant of their own, and unknown roles get nothing.
+	readerGrants := byRole[formatObjectID(roleResourceType.Id, 30)]
+	require.Len(t, readerGrants, 1)
+	require.Equal(t, formatEntitlementID(resource, "select", false), readerGrants[0].Entitlement.Id)
+	require.Empty(t, byRole[formatObjectID(roleResourceType.Id, 40)])
+	require.Len(t, byRole, 4)
+
+	// SELECT granted to PUBLIC is granted once to the PUBLIC role and expanded to every role through its member
+	// entitlement.
+	publicGrants := byRole[formatObjectID(roleResourceType.Id, publicRoleID)]
+	require.Len(t, publicGrants, 1)
+	require.Equal(t, formatEntitlementID(resource, "select", false), publicGrants[0].Entitlement.Id)
+	requireExpansion(t, publicGrants[0], formatEntitlementID(publicGrants[0].Principal, roleMemberSlug, false))
+	publicAnnos := annotations.Annotations(publicGrants[0].Annotations)
+	require.True(t, publicAnnos.Contains(&v2.GrantImmutable{}))
+
+	requireExpansion(t, readerGrants[0], formatEntitlementID(readerGrants[0].Principal, roleInheritSlug, false))
+
+	// Roles without members aren't expanded.
+	for _, g := range byRole[formatObjectID(roleResourceType.Id, 20)] {
+		require.Empty(t, g.Annotations)
+	}
+}
+
+func TestRoleGrantsForACLsDefaultPrivileges(t *testing.T) {
+	ctx := context.Background()
+
+	graph := postgres.NewRoleGraph([]*postgres.RoleModel{
+		{ID: 20, Name: "owner"},
+	}, true)
+
+	resource := &v2.Resource{
+		Id: &v2.ResourceId{
+			ResourceType: functionResourceType.Id,
+			Resource:     formatObjectID(functionResourceType.Id, 100),
+		},
+	}
+
+	// A function without an ACL can be executed by PUBLIC.
+	function := &postgres.FunctionModel{ID: 100, Name: "total", OwnerID: 20}
+	grants, err := roleGrantsForACLs(ctx, graph, resource, function, nil)
+	require.NoError(t, err)
+
+	var publicGrants []*v2.Grant
+	for _, g := range grants {
+		if g.Principal.Id.Resource == formatObjectID(roleResourceType.Id, publicRoleID) {
+			publicGrants = append(publicGrants, g)
+		}
+	}
+	require.Len(t, publicGrants, 1)
+	require.Equal(t, formatEntitlementID(resource, "execute", false), publicGrants[0].Entitlement.Id)
+
+	// Once the ACL is set, PUBLIC only has what it lists.
+	function.ACLs = []string{"owner=X/owner"}
+	acl, err := postgres.NewACL(function.ACLs[0])
+	require.NoError(t, err)
+	grants, err = roleGrantsForACLs(ctx, graph, resource, function, []*postgres.ACL{acl})
+	require.NoError(t, err)
+	for _, g := range grants {
+		require.NotEqual(t, formatObjectID(roleResourceType.Id, publicRoleID), g.Principal.Id.Resource)
+	}
+}
+
+func TestRoleExpansionBeforeMembershipOptions(t *testing.T) {
+	graph := postgres.NewRoleGraph([]*postgres.RoleModel{
+		{ID: 30, Name: "readers"},
+		{ID: 40, Name: "alice", MemberOf: []int64{30}, InheritFrom: []int64{30}},
+		{ID: 50, Name: "bob", MemberOf: []int64{30}},
+	}, false)
+
+	role := &v2.Resource{
+		Id: &v2.ResourceId{
+			ResourceType: roleResourceType.Id,
+			Resource:     formatObjectID(roleResourceType.Id, 30),
+		},
+	}
+
+	// Grants expand through the inherit entitlement, which bob, a NOINHERIT member, doesn't hold.
+	expansion := roleExpansion(graph, role, 30)
+	require.NotNil(t, expansion)
+	require.Equal(t, []string{formatEntitlementID(role, roleInheritSlug, false)}, expansion.EntitlementIds)
+	require.Nil(t, roleExpansion(graph, role, 40))
+}
+
+func requireExpansion(t *testing.T, g *v2.Grant, entitlementID string) {
+	expansion := &v2.GrantExpandable{}
+	annos := annotations.Annotations(g.Annotations)
+	ok, err := annos.Pick(expansion)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{entitlementID}, expansion.EntitlementIds)
+}
+
+func entitlementsForAllPrivs(t *testing.T, resource *v2.Resource, privs postgres.PrivilegeSet) []*v2.Entitlement {
+	entitlements, err := entitlementsForPrivs(context.Background(), resource, privs)
+	require.NoError(t, err)
+	return entitlements
+}
@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/conductorone/baton-postgresql/pkg/postgres"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -19,8 +20,9 @@ var largeObjectResourceType = &v2.ResourceType{
 
 type largeObjectSyncer struct {
 	resourceType *v2.ResourceType
-	client       *postgres.Client
+	clientPool   *postgres.ClientDatabasesPool
 	enabled      bool
+	cache        *syncCache
 }
 
 func (r *largeObjectSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -30,15 +32,26 @@ func (r *largeObjectSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
 func (r *largeObjectSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var err error
 
-	if parentResourceID != nil {
-		return nil, "", nil, fmt.Errorf("unexpected parent resource ID on large object")
+	if parentResourceID == nil || !r.enabled {
+		return nil, "", nil, nil
 	}
 
-	if !r.enabled {
-		return nil, "", nil, nil
+	if parentResourceID.ResourceType != databaseResourceType.Id {
+		return nil, "", nil, fmt.Errorf("invalid parent resource ID on large object")
+	}
+
+	dbId, err := parseObjectID(parentResourceID.Resource)
+	if err != nil {
+		return nil, "", nil, err
 	}
 
-	largeObjects, nextPageToken, err := r.client.ListLargeObjects(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	db := strconv.FormatInt(dbId, 10)
+	client, dbName, err := r.clientPool.Get(ctx, db)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	largeObjects, nextPageToken, err := client.ListLargeObjects(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
 	if err != nil {
 		return nil, "", nil, err
 	}
@@ -48,10 +61,10 @@ func (r *largeObjectSyncer) List(ctx context.Context, parentResourceID *v2.Resou
 		var annos annotations.Annotations
 
 		ret = append(ret, &v2.Resource{
-			DisplayName: fmt.Sprintf("%d", o.ID),
+			DisplayName: fmt.Sprintf("%s - %d", dbName, o.ID),
 			Id: &v2.ResourceId{
 				ResourceType: r.resourceType.Id,
-				Resource:     formatObjectID(r.resourceType.Id, o.ID),
+				Resource:     formatWithDatabaseID(largeObjectResourceType.Id, db, o.ID),
 			},
 			ParentResourceId: parentResourceID,
 			Annotations:      annos,
@@ -67,37 +80,131 @@ func (r *largeObjectSyncer) Entitlements(ctx context.Context, resource *v2.Resou
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	return ens, "", nil, nil
 }
 
 func (r *largeObjectSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
-	rID, err := parseObjectID(resource.Id.Resource)
+	db, rID, err := parseWithDatabaseID(resource.Id.Resource)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	largeObject, err := r.client.GetLargeObject(ctx, rID)
+	client, _, err := r.clientPool.Get(ctx, db)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, err := r.client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	largeObject, err := client.GetLargeObject(ctx, rID)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	ret, err := roleGrantsForPrivileges(ctx, r.client, resource, roles, largeObject)
+	graph, err := r.cache.roleGraph(ctx, client)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	ret, err := roleGrantsForPrivileges(ctx, graph, resource, largeObject)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	ret = append(ret, ownerGrant(resource, largeObject))
+
+	return ret, "", nil, nil
+}
+
+func (r *largeObjectSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-postgres: only users and roles can have large object granted")
+	}
+
+	_, _, privilegeName, isGrant, err := parseEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbClient, _, err := r.clientPool.Get(ctx, dbId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	largeObject, err := dbClient.GetLargeObject(ctx, rID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetLargeObjectOwner(ctx, largeObject.ID, principal.DisplayName)
+		}
+		return dbClient.GrantLargeObject(ctx, largeObject.ID, principal.DisplayName, privilegeName, isGrant)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []*v2.Grant{
+		{
+			Id:          fmt.Sprintf("%s:%s:%s", entitlement.Id, principal.Id.ResourceType, principal.Id.Resource),
+			Entitlement: entitlement,
+			Principal:   principal,
+		},
+	}, nil, nil
+}
+
+func (r *largeObjectSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	entitlement := grant.Entitlement
+	principal := grant.Principal
+
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, fmt.Errorf("baton-postgres: only users and roles can have large object revoked")
+	}
+
+	_, _, privilegeName, isGrant, err := parseEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
+	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	dbClient, _, err := r.clientPool.Get(ctx, dbId)
+	if err != nil {
+		return nil, err
+	}
+
+	largeObject, err := dbClient.GetLargeObject(ctx, rID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dbClient.RevokeLargeObject(ctx, largeObject.ID, principal.DisplayName, privilegeName, isGrant)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newLargeObjectSyncer(ctx context.Context, c *postgres.Client, enabled bool) *largeObjectSyncer {
+func newLargeObjectSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, enabled bool, cache *syncCache) *largeObjectSyncer {
 	return &largeObjectSyncer{
 		resourceType: largeObjectResourceType,
-		client:       c,
+		clientPool:   c,
 		enabled:      enabled,
+		cache:        cache,
 	}
 }
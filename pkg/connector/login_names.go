@@ -0,0 +1,122 @@
+package connector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+)
+
+// defaultLoginNameTemplate names a provisioned role after the user's email, which is how accounts were named before
+// the template was configurable.
+const defaultLoginNameTemplate = "{{ .email }}"
+
+// maxIdentifierLength is NAMEDATALEN - 1, the longest identifier PostgreSQL keeps before silently truncating it.
+const maxIdentifierLength = 63
+
+// loginNameHashLength is the number of hex characters of the full name's hash kept when a name is truncated, so
+// names sharing a long prefix still end up as different roles.
+const loginNameHashLength = 8
+
+var invalidIdentifierChars = regexp.MustCompile(`[^a-z0-9_]`)
+
+var loginNameFuncs = template.FuncMap{
+	// localpart returns the part of an email address before the @.
+	"localpart": func(s string) string {
+		local, _, _ := strings.Cut(s, "@")
+		return local
+	},
+	"lower": strings.ToLower,
+	// identifier makes s usable as an unquoted identifier: lowercase, with any character other than a-z, 0-9
+	// and _ replaced by _ and a leading digit prefixed with _.
+	"identifier": func(s string) string {
+		s = invalidIdentifierChars.ReplaceAllString(strings.ToLower(s), "_")
+		if s != "" && s[0] >= '0' && s[0] <= '9' {
+			s = "_" + s
+		}
+		return s
+	},
+}
+
+// parseLoginNameTemplate parses the template used to derive a role name from the profile of an account being
+// created, e.g. `app_{{ .email | localpart | identifier }}`.
+func parseLoginNameTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultLoginNameTemplate
+	}
+
+	tmpl, err := template.New("login-name").Funcs(loginNameFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("baton-postgres: invalid login name template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// accountEmail returns the email of an account being created: the email field of the account creation schema
+// when it is filled, and the C1 user's login otherwise.
+func accountEmail(login string, profile *structpb.Struct) string {
+	if value, ok := profile.GetFields()["email"]; ok && value.GetStringValue() != "" {
+		return value.GetStringValue()
+	}
+	return login
+}
+
+// deriveLoginName renders tmpl with the account profile. Besides the profile fields, the template can use .login,
+// the C1 user's login, and .email as returned by accountEmail. Names longer than PostgreSQL allows are truncated.
+func deriveLoginName(tmpl *template.Template, login string, profile *structpb.Struct) (string, error) {
+	data := profile.AsMap()
+	data["login"] = login
+	data["email"] = accountEmail(login, profile)
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("baton-postgres: error deriving login name: %w", err)
+	}
+
+	name := strings.TrimSpace(b.String())
+	if name == "" || name == "<no value>" {
+		return "", fmt.Errorf("baton-postgres: login name template produced an empty name")
+	}
+
+	return truncateIdentifier(name), nil
+}
+
+// truncateIdentifier shortens name to maxIdentifierLength bytes, replacing its tail with a hash of the full name.
+func truncateIdentifier(name string) string {
+	if len(name) <= maxIdentifierLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	suffix := "_" + hex.EncodeToString(sum[:])[:loginNameHashLength]
+
+	prefix := name[:maxIdentifierLength-len(suffix)]
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+
+	return prefix + suffix
+}
+
+// roleBelongsTo reports whether an existing role is the account of email: it is named after the email, or the email
+// is stored in its comment as CreateAccount does. A login name template can derive the same name from different
+// emails, so a role found under a derived name isn't necessarily the account being created.
+func roleBelongsTo(roleModel *postgres.RoleModel, email string) bool {
+	if strings.EqualFold(roleModel.Name, email) {
+		return true
+	}
+
+	if roleModel.Comment == nil {
+		return false
+	}
+
+	return strings.EqualFold(findEmail(*roleModel.Comment), email)
+}
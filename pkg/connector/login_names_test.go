@@ -0,0 +1,62 @@
+package connector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+)
+
+func TestDeriveLoginName(t *testing.T) {
+	profile, err := structpb.NewStruct(map[string]interface{}{"email": "Jane.Doe-Smith@corp.com"})
+	require.NoError(t, err)
+
+	tmpl, err := parseLoginNameTemplate("")
+	require.NoError(t, err)
+	name, err := deriveLoginName(tmpl, "jane@login.com", profile)
+	require.NoError(t, err)
+	require.Equal(t, "Jane.Doe-Smith@corp.com", name)
+
+	// Without an email field the C1 login is used as the email.
+	name, err = deriveLoginName(tmpl, "jane@login.com", nil)
+	require.NoError(t, err)
+	require.Equal(t, "jane@login.com", name)
+
+	tmpl, err = parseLoginNameTemplate("app_{{ .email | localpart | identifier }}")
+	require.NoError(t, err)
+	name, err = deriveLoginName(tmpl, "jane@login.com", profile)
+	require.NoError(t, err)
+	require.Equal(t, "app_jane_doe_smith", name)
+
+	tmpl, err = parseLoginNameTemplate("{{ .team }}")
+	require.NoError(t, err)
+	_, err = deriveLoginName(tmpl, "jane@login.com", profile)
+	require.Error(t, err)
+
+	_, err = parseLoginNameTemplate("{{ .email | nope }}")
+	require.Error(t, err)
+}
+
+func TestTruncateIdentifier(t *testing.T) {
+	require.Equal(t, "short", truncateIdentifier("short"))
+
+	long := strings.Repeat("a", 70)
+	truncated := truncateIdentifier(long)
+	require.Len(t, truncated, maxIdentifierLength)
+	require.NotEqual(t, truncated, truncateIdentifier(long+"b"))
+	require.Equal(t, truncated, truncateIdentifier(long))
+}
+
+func TestRoleBelongsTo(t *testing.T) {
+	comment := "jane.doe@corp.com"
+	role := &postgres.RoleModel{Name: "app_jane_doe", Comment: &comment}
+	require.True(t, roleBelongsTo(role, "Jane.Doe@corp.com"))
+	// jane_doe@corp.com derives the same name with app_{{ .email | localpart | identifier }}.
+	require.False(t, roleBelongsTo(role, "jane_doe@corp.com"))
+
+	require.False(t, roleBelongsTo(&postgres.RoleModel{Name: "app_jane_doe"}, "jane.doe@corp.com"))
+	require.True(t, roleBelongsTo(&postgres.RoleModel{Name: "jane.doe@corp.com"}, "jane.doe@corp.com"))
+}
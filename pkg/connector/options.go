@@ -0,0 +1,85 @@
+package connector
+
+// Option configures how the connector provisions and classifies roles. Options not given keep their defaults.
+type Option func(o *options)
+
+type options struct {
+	allowSchemaCascade         bool
+	schemaOwner                string
+	groupNoLoginRoles          bool
+	groupRolePattern           string
+	successorRole              string
+	loginNameTemplate          string
+	emailSources               []string
+	emailSecurityLabelProvider string
+	emailLookupQuery           string
+	accountTypeRules           []string
+	protectedRoles             []string
+}
+
+// WithAllowSchemaCascade allows deleting non-empty schemas, dropping every object they contain.
+func WithAllowSchemaCascade(allow bool) Option {
+	return func(o *options) {
+		o.allowSchemaCascade = allow
+	}
+}
+
+// WithSchemaOwner sets the role that owns the schemas the connector creates. Without it, schemas are owned by the
+// role the connector connects as.
+func WithSchemaOwner(role string) Option {
+	return func(o *options) {
+		o.schemaOwner = role
+	}
+}
+
+// WithGroupNoLoginRoles syncs NOLOGIN roles as groups.
+func WithGroupNoLoginRoles(group bool) Option {
+	return func(o *options) {
+		o.groupNoLoginRoles = group
+	}
+}
+
+// WithGroupRolePattern syncs roles whose name matches pattern as groups.
+func WithGroupRolePattern(pattern string) Option {
+	return func(o *options) {
+		o.groupRolePattern = pattern
+	}
+}
+
+// WithSuccessorRole sets the role that takes over the objects of deleted roles.
+func WithSuccessorRole(role string) Option {
+	return func(o *options) {
+		o.successorRole = role
+	}
+}
+
+// WithLoginNameTemplate sets the template deriving the name of provisioned roles.
+func WithLoginNameTemplate(text string) Option {
+	return func(o *options) {
+		o.loginNameTemplate = text
+	}
+}
+
+// WithEmailSources sets where role emails are looked up, in order. labelProvider limits security labels to one
+// provider and lookupQuery is the query used by the query source.
+func WithEmailSources(sources []string, labelProvider string, lookupQuery string) Option {
+	return func(o *options) {
+		o.emailSources = sources
+		o.emailSecurityLabelProvider = labelProvider
+		o.emailLookupQuery = lookupQuery
+	}
+}
+
+// WithAccountTypeRules sets the rules classifying roles as human, service or system accounts.
+func WithAccountTypeRules(rules []string) Option {
+	return func(o *options) {
+		o.accountTypeRules = rules
+	}
+}
+
+// WithProtectedRoles sets the roles the connector refuses to delete, disable or revoke privileges from.
+func WithProtectedRoles(roles []string) Option {
+	return func(o *options) {
+		o.protectedRoles = roles
+	}
+}
@@ -20,6 +20,7 @@ var procedureResourceType = &v2.ResourceType{
 type procedureSyncer struct {
 	resourceType *v2.ResourceType
 	clientPool   *postgres.ClientDatabasesPool
+	cache        *syncCache
 }
 
 func (r *procedureSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -76,6 +77,8 @@ func (r *procedureSyncer) Entitlements(ctx context.Context, resource *v2.Resourc
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	return ens, "", nil, nil
 }
 
@@ -95,17 +98,19 @@ func (r *procedureSyncer) Grants(ctx context.Context, resource *v2.Resource, pTo
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	graph, err := r.cache.roleGraph(ctx, client)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, procedure)
+	ret, err := roleGrantsForPrivileges(ctx, graph, resource, procedure)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	ret = append(ret, ownerGrant(resource, procedure))
+
+	return ret, "", nil, nil
 }
 
 func (r *procedureSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
@@ -133,10 +138,16 @@ func (r *procedureSyncer) Grant(ctx context.Context, principal *v2.Resource, ent
 		return nil, nil, err
 	}
 
-	err = dbClient.GrantProcedure(ctx, procedure.Schema, procedure, principal.DisplayName, privilegeName, isGrant)
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetProcedureOwner(ctx, procedure.Schema, procedure, principal.DisplayName)
+		}
+		return dbClient.GrantProcedure(ctx, procedure.Schema, procedure, principal.DisplayName, privilegeName, isGrant)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
+
 	return []*v2.Grant{
 		{
 			Id:          fmt.Sprintf("%s:%s:%s", entitlement.Id, principal.Id.ResourceType, principal.Id.Resource),
@@ -159,6 +170,10 @@ func (r *procedureSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotati
 		return nil, err
 	}
 
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
 	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
 	if err != nil {
 		return nil, err
@@ -175,12 +190,17 @@ func (r *procedureSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotati
 	}
 
 	err = dbClient.RevokeProcedure(ctx, procedure.Schema, procedure, principal.DisplayName, privilegeName, isGrant)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newProcedureSyncer(ctx context.Context, c *postgres.ClientDatabasesPool) *procedureSyncer {
+func newProcedureSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, cache *syncCache) *procedureSyncer {
 	return &procedureSyncer{
 		resourceType: procedureResourceType,
 		clientPool:   c,
+		cache:        cache,
 	}
 }
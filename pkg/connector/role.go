@@ -2,9 +2,13 @@ package connector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/conductorone/baton-postgresql/pkg/postgres"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -13,6 +17,9 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/crypto"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	sdkResource "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 var roleResourceType = &v2.ResourceType{
@@ -22,24 +29,119 @@ var roleResourceType = &v2.ResourceType{
 	Annotations: nil,
 }
 
+const (
+	roleMemberSlug  = "member"
+	roleAdminSlug   = "admin"
+	roleInheritSlug = "inherit"
+	roleSetSlug     = "set"
+)
+
+// publicRoleID is the OID aclexplode reports as the grantee of privileges granted to PUBLIC. PUBLIC is synced as a
+// group role with this ID that every role is a member of, so those privileges are one set of grants expanded to all
+// roles.
+const publicRoleID = 0
+
+var (
+	errPublicRole        = errors.New("baton-postgres: PUBLIC isn't a role and can't be changed")
+	errMembershipOptions = errors.New("baton-postgres: inherit and set can only be granted per membership from PostgreSQL 16, before that they follow the member's role attributes")
+)
+
 type roleSyncer struct {
-	resourceType *v2.ResourceType
-	client       *postgres.Client
+	resourceType      *v2.ResourceType
+	clientPool        *postgres.ClientDatabasesPool
+	client            *postgres.Client
+	groupNoLoginRoles bool
+	groupRolePattern  *regexp.Regexp
+	successorRole     string
+	loginNameTemplate *template.Template
+	emails            *emailResolver
+	accountTypes      *accountTypeClassifier
+	cache             *syncCache
 }
 
 func (r *roleSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
 	return roleResourceType
 }
 
-func (r *roleSyncer) makeResource(ctx context.Context, roleModel *postgres.RoleModel) (*v2.Resource, error) {
+// roleProfile builds the user trait profile of a role. Settings made with ALTER ROLE ... SET are keyed by the
+// database they apply in, or "*" when they apply in every database.
+func roleProfile(roleModel *postgres.RoleModel) map[string]interface{} {
+	profile := map[string]interface{}{
+		"connection_limit": roleModel.ConnectionLimit,
+	}
+
+	if roleModel.ValidUntil != nil {
+		profile["valid_until"] = roleModel.ValidUntil.UTC().Format(time.RFC3339)
+	}
+
+	if roleModel.Comment != nil {
+		profile["comment"] = *roleModel.Comment
+	}
+
+	if len(roleModel.Settings) > 0 {
+		settings := make(map[string]interface{}, len(roleModel.Settings))
+		for database, config := range roleModel.Settings {
+			values := make([]interface{}, 0, len(config))
+			for _, c := range config {
+				values = append(values, c)
+			}
+			settings[database] = values
+		}
+		profile["settings"] = settings
+	}
+
+	return profile
+}
+
+// isGroupRole reports whether a role is exposed as a group with membership entitlements. Roles with members
+// always are, which is looked up in the role graph cached for the sync. NOLOGIN roles, when enabled, and roles
+// matching the configured name pattern are too, so that an empty group can still receive its first member.
+func (r *roleSyncer) isGroupRole(ctx context.Context, roleModel *postgres.RoleModel) (bool, error) {
+	if r.groupNoLoginRoles && !roleModel.CanLogin {
+		return true, nil
+	}
+
+	if r.groupRolePattern != nil && r.groupRolePattern.MatchString(roleModel.Name) {
+		return true, nil
+	}
+
+	graph, err := r.cache.roleGraph(ctx, r.client)
+	if err != nil {
+		return false, err
+	}
+
+	return graph.HasMembers(roleModel.ID), nil
+}
+
+// rolePage holds what makeResource needs to know about a page of roles that is looked up once for the whole page.
+type rolePage struct {
+	emails      map[string]string
+	markerRoles map[string]int64
+}
+
+func (r *roleSyncer) loadRolePage(ctx context.Context, roles []*postgres.RoleModel) (*rolePage, error) {
+	emails, err := r.emails.lookup(ctx, r.client, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	markerRoles, err := r.accountTypes.markerRoles(ctx, r.client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rolePage{emails: emails, markerRoles: markerRoles}, nil
+}
+
+func (r *roleSyncer) makeResource(ctx context.Context, roleModel *postgres.RoleModel, page *rolePage) (*v2.Resource, error) {
 	var annos annotations.Annotations
 
-	hasMembers, err := r.client.RoleHasMembers(ctx, roleModel.ID)
+	isGroup, err := r.isGroupRole(ctx, roleModel)
 	if err != nil {
 		return nil, err
 	}
 
-	if hasMembers {
+	if isGroup {
 		gt, err := sdkResource.NewGroupTrait()
 		if err != nil {
 			return nil, err
@@ -47,23 +149,20 @@ func (r *roleSyncer) makeResource(ctx context.Context, roleModel *postgres.RoleM
 		annos.Update(gt)
 	}
 
-	traitOptions := []sdkResource.UserTraitOption{
-		sdkResource.WithStatus(v2.UserTrait_Status_STATUS_ENABLED),
+	status := v2.UserTrait_Status_STATUS_ENABLED
+	if !roleModel.CanLogin || roleModel.IsExpired(time.Now()) {
+		status = v2.UserTrait_Status_STATUS_DISABLED
 	}
 
-	switch {
-	case roleModel.Name == "postgres":
-		traitOptions = append(traitOptions, sdkResource.WithAccountType(v2.UserTrait_ACCOUNT_TYPE_SYSTEM))
-	case roleModel.CanLogin:
-		traitOptions = append(traitOptions, sdkResource.WithAccountType(v2.UserTrait_ACCOUNT_TYPE_HUMAN))
-	default:
-		traitOptions = append(traitOptions, sdkResource.WithAccountType(v2.UserTrait_ACCOUNT_TYPE_SERVICE))
+	traitOptions := []sdkResource.UserTraitOption{
+		sdkResource.WithStatus(status),
 	}
 
+	traitOptions = append(traitOptions, sdkResource.WithAccountType(r.accountTypes.classify(roleModel, page.markerRoles)))
+	traitOptions = append(traitOptions, sdkResource.WithUserProfile(roleProfile(roleModel)))
 	traitOptions = append(traitOptions, sdkResource.WithUserLogin(roleModel.Name))
-	parts := strings.SplitN(roleModel.Name, "@", 2)
-	if len(parts) == 2 {
-		traitOptions = append(traitOptions, sdkResource.WithEmail(roleModel.Name, true))
+	if email := r.emails.resolve(roleModel, page.emails); email != "" {
+		traitOptions = append(traitOptions, sdkResource.WithEmail(email, true))
 	}
 	ut, err := sdkResource.NewUserTrait(traitOptions...)
 	if err != nil {
@@ -87,6 +186,43 @@ func (r *roleSyncer) makeResource(ctx context.Context, roleModel *postgres.RoleM
 	}, nil
 }
 
+// roleResource builds the resource for a single role.
+func (r *roleSyncer) roleResource(ctx context.Context, roleModel *postgres.RoleModel) (*v2.Resource, error) {
+	page, err := r.loadRolePage(ctx, []*postgres.RoleModel{roleModel})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.makeResource(ctx, roleModel, page)
+}
+
+// publicRoleResource builds the resource of PUBLIC, a group of every role.
+func publicRoleResource() (*v2.Resource, error) {
+	gt, err := sdkResource.NewGroupTrait()
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := sdkResource.NewRoleTrait()
+	if err != nil {
+		return nil, err
+	}
+
+	return &v2.Resource{
+		DisplayName: "PUBLIC",
+		Id: &v2.ResourceId{
+			ResourceType: roleResourceType.Id,
+			Resource:     formatObjectID(roleResourceType.Id, publicRoleID),
+		},
+		Annotations: annotations.New(gt, rt),
+	}, nil
+}
+
+// isPublicRole reports whether id is the ID of the PUBLIC role.
+func isPublicRole(id string) bool {
+	return id == formatObjectID(roleResourceType.Id, publicRoleID)
+}
+
 func (r *roleSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var err error
 
@@ -100,9 +236,22 @@ func (r *roleSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId,
 		return nil, "", nil, err
 	}
 
+	page, err := r.loadRolePage(ctx, roles)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
 	var ret []*v2.Resource
+	if pToken.Token == "" {
+		public, err := publicRoleResource()
+		if err != nil {
+			return nil, "", nil, err
+		}
+		ret = append(ret, public)
+	}
+
 	for _, o := range roles {
-		resource, err := r.makeResource(ctx, o)
+		resource, err := r.makeResource(ctx, o, page)
 		if err != nil {
 			return nil, "", nil, err
 		}
@@ -115,6 +264,21 @@ func (r *roleSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId,
 func (r *roleSyncer) Entitlements(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
 	var ret []*v2.Entitlement
 
+	if isPublicRole(resource.Id.Resource) {
+		return []*v2.Entitlement{
+			{
+				Resource:    resource,
+				Id:          formatEntitlementID(resource, roleMemberSlug, false),
+				DisplayName: "Member",
+				Description: "Holds the privileges granted to PUBLIC, as every role does",
+				GrantableTo: []*v2.ResourceType{roleResourceType},
+				Annotations: annotations.New(&v2.EntitlementImmutable{}),
+				Purpose:     v2.Entitlement_PURPOSE_VALUE_ASSIGNMENT,
+				Slug:        roleMemberSlug,
+			},
+		}, "", nil, nil
+	}
+
 	annos := annotations.Annotations(resource.Annotations)
 
 	gt := &v2.GroupTrait{}
@@ -126,22 +290,54 @@ func (r *roleSyncer) Entitlements(ctx context.Context, resource *v2.Resource, pT
 	if ok {
 		ret = append(ret, &v2.Entitlement{
 			Resource:    resource,
-			Id:          formatEntitlementID(resource, "member", false),
+			Id:          formatEntitlementID(resource, roleMemberSlug, false),
 			DisplayName: "Member",
 			Description: fmt.Sprintf("Is assigned the %s role", resource.DisplayName),
 			GrantableTo: []*v2.ResourceType{roleResourceType},
 			Purpose:     v2.Entitlement_PURPOSE_VALUE_ASSIGNMENT,
-			Slug:        "member",
+			Slug:        roleMemberSlug,
 		})
 		ret = append(ret, &v2.Entitlement{
 			Resource:    resource,
-			Id:          formatEntitlementID(resource, "admin", false),
+			Id:          formatEntitlementID(resource, roleAdminSlug, false),
 			DisplayName: "Admin",
 			Description: fmt.Sprintf("Can grant the %s role to other roles", resource.DisplayName),
 			GrantableTo: []*v2.ResourceType{roleResourceType},
 			Purpose:     v2.Entitlement_PURPOSE_VALUE_ASSIGNMENT,
-			Slug:        "admin",
+			Slug:        roleAdminSlug,
 		})
+
+		// PostgreSQL 16 tracks inheritance and SET ROLE per membership, so they can be granted on their own. Before
+		// that, members inherit the role's privileges when they have the INHERIT attribute, and the inherit
+		// entitlement only records which ones do.
+		supported, err := r.client.SupportsMembershipOptions(ctx)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		inherit := &v2.Entitlement{
+			Resource:    resource,
+			Id:          formatEntitlementID(resource, roleInheritSlug, false),
+			DisplayName: "Inherit",
+			Description: fmt.Sprintf("Inherits the privileges of the %s role", resource.DisplayName),
+			GrantableTo: []*v2.ResourceType{roleResourceType},
+			Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+			Slug:        roleInheritSlug,
+		}
+		if !supported {
+			inherit.Annotations = annotations.New(&v2.EntitlementImmutable{})
+		}
+		ret = append(ret, inherit)
+		if supported {
+			ret = append(ret, &v2.Entitlement{
+				Resource:    resource,
+				Id:          formatEntitlementID(resource, roleSetSlug, false),
+				DisplayName: "Set",
+				Description: fmt.Sprintf("Can SET ROLE to the %s role", resource.DisplayName),
+				GrantableTo: []*v2.ResourceType{roleResourceType},
+				Purpose:     v2.Entitlement_PURPOSE_VALUE_PERMISSION,
+				Slug:        roleSetSlug,
+			})
+		}
 	}
 
 	return ret, "", nil, nil
@@ -162,6 +358,10 @@ func (r *roleSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken *
 		return nil, "", nil, nil
 	}
 
+	if isPublicRole(resource.Id.Resource) {
+		return r.publicRoleGrants(ctx, resource, pToken)
+	}
+
 	roleID, err := parseObjectID(resource.Id.Resource)
 	if err != nil {
 		return nil, "", nil, err
@@ -172,27 +372,97 @@ func (r *roleSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken *
 		return nil, "", nil, err
 	}
 
-	var eID string
+	supportsOptions, err := r.client.SupportsMembershipOptions(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	graph, err := r.cache.roleGraph(ctx, r.client)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	memberEntitlementID := formatEntitlementID(resource, roleMemberSlug, false)
+	adminEntitlementID := formatEntitlementID(resource, roleAdminSlug, false)
+	inheritEntitlementID := formatEntitlementID(resource, roleInheritSlug, false)
+	setEntitlementID := formatEntitlementID(resource, roleSetSlug, false)
 	for _, m := range roleMembers {
+		principal := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: roleResourceType.Id,
+				Resource:     formatObjectID(roleResourceType.Id, m.ID),
+			},
+		}
+
+		// Every member gets the member grant, members holding the admin option get the admin grant as well.
+		eIDs := []string{memberEntitlementID}
 		if m.IsRoleAdmin() {
-			eID = formatEntitlementID(resource, "admin", false)
-		} else {
-			eID = formatEntitlementID(resource, "member", false)
+			eIDs = append(eIDs, adminEntitlementID)
+		}
+		if m.InheritsRole() {
+			eIDs = append(eIDs, inheritEntitlementID)
+		}
+		if supportsOptions && m.CanSetRole() {
+			eIDs = append(eIDs, setEntitlementID)
 		}
 
+		for _, eID := range eIDs {
+			g := &v2.Grant{
+				Id: formatGrantID(eID, principal.Id),
+				Entitlement: &v2.Entitlement{
+					Id:       eID,
+					Resource: resource,
+				},
+				Principal: principal,
+			}
+
+			// A member that is itself a group passes the membership on to its own members, which are then
+			// members of this role too. The admin option isn't passed on.
+			var annos annotations.Annotations
+			if eID != adminEntitlementID && graph.HasMembers(m.ID) {
+				slug := eID[strings.LastIndex(eID, ":")+1:]
+				annos.Update(&v2.GrantExpandable{
+					EntitlementIds: []string{formatEntitlementID(principal, slug, false)},
+				})
+			}
+			// Before PostgreSQL 16 inheritance follows the member's INHERIT attribute, not the membership.
+			if eID == inheritEntitlementID && !supportsOptions {
+				annos.Update(&v2.GrantImmutable{})
+			}
+			g.Annotations = annos
+
+			ret = append(ret, g)
+		}
+	}
+
+	return ret, nextPageToken, nil, nil
+}
+
+// publicRoleGrants returns the memberships of every role in PUBLIC.
+func (r *roleSyncer) publicRoleGrants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	roles, nextPageToken, err := r.client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	eID := formatEntitlementID(resource, roleMemberSlug, false)
+	ret := make([]*v2.Grant, 0, len(roles))
+	for _, role := range roles {
 		principal := &v2.Resource{
 			Id: &v2.ResourceId{
 				ResourceType: roleResourceType.Id,
-				Resource:     formatObjectID(roleResourceType.Id, m.ID),
+				Resource:     formatObjectID(roleResourceType.Id, role.ID),
 			},
 		}
+
 		ret = append(ret, &v2.Grant{
 			Id: formatGrantID(eID, principal.Id),
 			Entitlement: &v2.Entitlement{
 				Id:       eID,
 				Resource: resource,
 			},
-			Principal: principal,
+			Principal:   principal,
+			Annotations: annotations.New(&v2.GrantImmutable{}),
 		})
 	}
 
@@ -209,13 +479,130 @@ func (r *roleSyncer) Delete(ctx context.Context, resourceId *v2.ResourceId) (ann
 		return nil, err
 	}
 
+	if roleId == publicRoleID {
+		return nil, errPublicRole
+	}
+
 	pgRole, err := r.client.GetRole(ctx, roleId)
 	if err != nil {
 		return nil, err
 	}
 
+	// Check before cleaning up, which would otherwise only fail per database.
+	if err := r.client.CheckProtectedRole(pgRole.Name); err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	summary, err := r.cleanupRole(ctx, pgRole.Name)
+	if summary != nil {
+		annos.Append(summary)
+	}
+	if err != nil {
+		return annos, err
+	}
+
 	err = r.client.DeleteRole(ctx, pgRole.Name)
-	return nil, err
+	return annos, err
+}
+
+// cleanupRole prepares roleName for DROP ROLE in every database the pool can reach. Objects it owns are handed
+// over to the successor role when one is configured; otherwise it refuses roles that own objects in any database
+// before revoking anything. Every privilege the role holds, including default privileges, is revoked. It returns a
+// summary of what was cleaned up per database. A database that fails doesn't stop the others from being cleaned up;
+// the summary then records its error and is returned along with the errors.
+func (r *roleSyncer) cleanupRole(ctx context.Context, roleName string) (*structpb.Struct, error) {
+	l := ctxzap.Extract(ctx)
+
+	if r.successorRole != "" && roleName == r.successorRole {
+		return nil, fmt.Errorf("baton-postgres: cannot delete the successor role %s", roleName)
+	}
+
+	// Without a successor, a role that owns objects can't be dropped. Check every database before revoking
+	// anything, so a delete that is bound to fail doesn't leave the role stripped of its privileges.
+	if r.successorRole == "" {
+		var owningDatabases []string
+		err := r.clientPool.ForEachDatabase(ctx, func(c *postgres.Client, dbName string) error {
+			ownsObjects, err := c.RoleOwnsObjects(ctx, roleName)
+			if err != nil {
+				return fmt.Errorf("baton-postgres: error checking objects owned by %s in database %s: %w", roleName, dbName, err)
+			}
+			if ownsObjects {
+				owningDatabases = append(owningDatabases, dbName)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(owningDatabases) > 0 {
+			return nil, fmt.Errorf("baton-postgres: cannot delete role %s: it owns objects in databases %s; configure a successor role or transfer ownership first",
+				roleName, strings.Join(owningDatabases, ", "))
+		}
+	}
+
+	var databases []interface{}
+	sharedReassigned := false
+	cleanupErr := r.clientPool.ForEachDatabase(ctx, func(c *postgres.Client, dbName string) error {
+		dbSummary := map[string]interface{}{
+			"database": dbName,
+		}
+		defer func() {
+			databases = append(databases, dbSummary)
+		}()
+
+		if r.successorRole != "" {
+			reassigned, err := c.ReassignOwned(ctx, roleName, r.successorRole, !sharedReassigned)
+			if err != nil {
+				err = fmt.Errorf("baton-postgres: error reassigning objects owned by %s in database %s: %w", roleName, dbName, err)
+				dbSummary["error"] = err.Error()
+				return err
+			}
+			sharedReassigned = true
+			l.Debug("reassigned owned objects", zap.String("role", roleName), zap.String("database", dbName), zap.Any("reassigned", reassigned))
+
+			objects := make(map[string]interface{}, len(reassigned))
+			for catalog, count := range reassigned {
+				objects[catalog] = count
+			}
+			dbSummary["reassigned"] = objects
+		}
+
+		cleanup, err := c.CleanupRoleGrants(ctx, roleName)
+		if err != nil {
+			err = fmt.Errorf("baton-postgres: error revoking grants from %s in database %s: %w", roleName, dbName, err)
+			dbSummary["error"] = err.Error()
+			return err
+		}
+
+		schemas := make([]interface{}, 0, len(cleanup.Schemas))
+		for _, schema := range cleanup.Schemas {
+			schemas = append(schemas, schema)
+		}
+		dbSummary["revoked_schemas"] = schemas
+		dbSummary["revoked_default_privileges"] = cleanup.DefaultPrivileges
+		if cleanup.Err != nil {
+			l.Warn("some grants could not be revoked", zap.String("role", roleName), zap.String("database", dbName), zap.Error(cleanup.Err))
+			dbSummary["error"] = cleanup.Err.Error()
+		}
+
+		return nil
+	})
+
+	summary := map[string]interface{}{
+		"role":      roleName,
+		"databases": databases,
+	}
+	if r.successorRole != "" {
+		summary["successor_role"] = r.successorRole
+	}
+
+	ret, err := structpb.NewStruct(summary)
+	if err != nil {
+		return nil, errors.Join(cleanupErr, err)
+	}
+
+	return ret, cleanupErr
 }
 
 func (r *roleSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
@@ -223,8 +610,7 @@ func (r *roleSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlem
 		return nil, nil, fmt.Errorf("baton-postgres: only users and roles can have roles granted")
 	}
 
-	// TODO: respect isGrant
-	_, roleIdStr, _, _, err := parseEntitlementID(entitlement.Id)
+	_, roleIdStr, privilegeName, _, err := parseEntitlementID(entitlement.Id)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -234,12 +620,16 @@ func (r *roleSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlem
 		return nil, nil, err
 	}
 
-	pgRole, err := r.client.GetRole(ctx, roleID)
+	principalId, err := parseObjectID(principal.Id.Resource)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	principalId, err := parseObjectID(principal.Id.Resource)
+	if roleID == publicRoleID || principalId == publicRoleID {
+		return nil, nil, errPublicRole
+	}
+
+	pgRole, err := r.client.GetRole(ctx, roleID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -248,16 +638,45 @@ func (r *roleSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlem
 	if err != nil {
 		return nil, nil, err
 	}
-	// TODO: respect duration if it's provided
-	err = r.client.GrantRole(ctx, pgRole.Name, pgPrincipal.Name)
-	return nil, nil, err
+	var grant func() error
+	switch privilegeName {
+	case roleInheritSlug, roleSetSlug:
+		supported, err := r.client.SupportsMembershipOptions(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !supported {
+			return nil, nil, errMembershipOptions
+		}
+
+		// Only add the requested option, keeping whatever options an existing membership already has.
+		inherit := privilegeName == roleInheritSlug
+		set := privilegeName == roleSetSlug
+		member, err := r.client.GetRoleMember(ctx, pgRole.ID, pgPrincipal.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if member != nil {
+			inherit = inherit || member.InheritsRole()
+			set = set || member.CanSetRole()
+		}
+		grant = func() error {
+			return r.client.GrantRoleWithOptions(ctx, pgRole.Name, pgPrincipal.Name, inherit, set)
+		}
+	default:
+		grant = func() error {
+			return r.client.GrantRole(ctx, pgRole.Name, pgPrincipal.Name, privilegeName == roleAdminSlug)
+		}
+	}
+
+	return nil, nil, grantWithExpiry(ctx, r.client, principal, entitlement, grant)
 }
 
 func (r *roleSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
 	entitlement := grant.Entitlement
 	principal := grant.Principal
 
-	_, roleIdStr, _, isGrant, err := parseEntitlementID(entitlement.Id)
+	_, roleIdStr, privilegeName, _, err := parseEntitlementID(entitlement.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -267,14 +686,39 @@ func (r *roleSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.A
 		return nil, err
 	}
 
+	if roleID == publicRoleID || isPublicRole(principal.Id.Resource) {
+		return nil, errPublicRole
+	}
+
+	if privilegeName == roleInheritSlug || privilegeName == roleSetSlug {
+		supported, err := r.client.SupportsMembershipOptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			return nil, errMembershipOptions
+		}
+	}
+
 	pgRole, err := r.client.GetRole(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
 
 	principalName := principal.DisplayName
-	err = r.client.RevokeRole(ctx, pgRole.Name, principalName, isGrant)
-	return nil, err
+	switch privilegeName {
+	case roleInheritSlug:
+		err = r.client.RevokeRoleOption(ctx, pgRole.Name, principalName, postgres.RoleOptionInherit)
+	case roleSetSlug:
+		err = r.client.RevokeRoleOption(ctx, pgRole.Name, principalName, postgres.RoleOptionSet)
+	default:
+		err = r.client.RevokeRole(ctx, pgRole.Name, principalName, privilegeName == roleAdminSlug)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.client, grant)
 }
 
 func (r *roleSyncer) RotateCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
@@ -302,6 +746,10 @@ func (r *roleSyncer) Rotate(
 		return nil, nil, err
 	}
 
+	if roleId == publicRoleID {
+		return nil, nil, errPublicRole
+	}
+
 	pgRole, err := r.client.GetRole(ctx, roleId)
 	if err != nil {
 		return nil, nil, err
@@ -326,8 +774,11 @@ func (r *roleSyncer) Rotate(
 
 func (r *roleSyncer) CreateAccountCapabilityDetails(ctx context.Context) (*v2.CredentialDetailsAccountProvisioning, annotations.Annotations, error) {
 	return &v2.CredentialDetailsAccountProvisioning{
-		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD},
-		PreferredCredentialOption:  v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
 	}, nil, nil
 }
 
@@ -341,10 +792,21 @@ func (r *roleSyncer) CreateAccount(
 	annotations.Annotations,
 	error,
 ) {
-	roleModel, err := r.client.GetRoleByName(ctx, accountInfo.GetLogin())
+	roleName, err := deriveLoginName(r.loginNameTemplate, accountInfo.GetLogin(), accountInfo.Profile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	email := accountEmail(accountInfo.GetLogin(), accountInfo.Profile)
+
+	roleModel, err := r.client.GetRoleByName(ctx, roleName)
 	if err == nil {
+		if !roleBelongsTo(roleModel, email) {
+			return nil, nil, nil, fmt.Errorf("baton-postgres: role %s already exists and belongs to another account than %s", roleName, email)
+		}
+
 		// user already exists. return that resource
-		resource, err := r.makeResource(ctx, roleModel)
+		resource, err := r.roleResource(ctx, roleModel)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -355,29 +817,35 @@ func (r *roleSyncer) CreateAccount(
 		return car, []*v2.PlaintextData{}, nil, nil
 	}
 
-	plainTextPassword, err := crypto.GeneratePassword(credentialOptions)
+	opts, noPassword, err := createUserOptions(accountInfo.Profile)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	ptd := &v2.PlaintextData{
-		Name:  "password",
-		Bytes: []byte(plainTextPassword),
-	}
-	// Default to C1 User's login as email
-	email := accountInfo.GetLogin()
-	// If the account provisioning schema has been filled, use the calculated email field
-	if accountInfo.Profile != nil {
-		profileMap := accountInfo.Profile.GetFields()
-		if value, ok := profileMap["email"]; ok && value.GetStringValue() != "" {
-			email = value.GetStringValue()
+
+	var plaintexts []*v2.PlaintextData
+	if !noPassword && credentialOptions.GetNoPassword() == nil {
+		plainTextPassword, err := crypto.GeneratePassword(credentialOptions)
+		if err != nil {
+			return nil, nil, nil, err
 		}
+		opts.Password = plainTextPassword
+		plaintexts = append(plaintexts, &v2.PlaintextData{
+			Name:  "password",
+			Bytes: []byte(plainTextPassword),
+		})
 	}
-	roleModel, err = r.client.CreateUser(ctx, email, plainTextPassword)
+
+	// Keep the email in the role's comment when it isn't the role name, so it can still be synced as the email.
+	if email != roleName {
+		opts.Comment = email
+	}
+
+	roleModel, err = r.client.CreateUserWithOptions(ctx, roleName, opts)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	resource, err := r.makeResource(ctx, roleModel)
+	resource, err := r.roleResource(ctx, roleModel)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -386,12 +854,63 @@ func (r *roleSyncer) CreateAccount(
 		Resource: resource,
 	}
 
-	return car, []*v2.PlaintextData{ptd}, nil, nil
+	return car, plaintexts, nil, nil
 }
 
-func newRoleSyncer(ctx context.Context, c *postgres.Client) *roleSyncer {
+// createUserOptions reads the optional account creation schema fields from the profile. It also reports whether
+// the user should be created without a password.
+func createUserOptions(profile *structpb.Struct) (*postgres.CreateUserOptions, bool, error) {
+	opts := &postgres.CreateUserOptions{}
+	fields := profile.GetFields()
+
+	for _, v := range fields["groups"].GetListValue().GetValues() {
+		if group := v.GetStringValue(); group != "" {
+			opts.Groups = append(opts.Groups, group)
+		}
+	}
+
+	if v, ok := fields["connection_limit"]; ok {
+		if _, isNumber := v.GetKind().(*structpb.Value_NumberValue); isNumber {
+			limit := int(v.GetNumberValue())
+			opts.ConnectionLimit = &limit
+		}
+	}
+
+	if v := fields["valid_until"].GetStringValue(); v != "" {
+		validUntil, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			validUntil, err = time.Parse(time.DateOnly, v)
+			if err != nil {
+				return nil, false, fmt.Errorf("baton-postgres: invalid valid_until %q, expected a date or RFC 3339 time", v)
+			}
+		}
+		opts.ValidUntil = &validUntil
+	}
+
+	return opts, fields["no_password"].GetBoolValue(), nil
+}
+
+func newRoleSyncer(
+	ctx context.Context,
+	c *postgres.ClientDatabasesPool,
+	groupNoLoginRoles bool,
+	groupRolePattern *regexp.Regexp,
+	successorRole string,
+	loginNameTemplate *template.Template,
+	emails *emailResolver,
+	accountTypes *accountTypeClassifier,
+	cache *syncCache,
+) *roleSyncer {
 	return &roleSyncer{
-		resourceType: roleResourceType,
-		client:       c,
+		resourceType:      roleResourceType,
+		clientPool:        c,
+		client:            c.Default(ctx),
+		groupNoLoginRoles: groupNoLoginRoles,
+		groupRolePattern:  groupRolePattern,
+		successorRole:     successorRole,
+		loginNameTemplate: loginNameTemplate,
+		emails:            emails,
+		accountTypes:      accountTypes,
+		cache:             cache,
 	}
 }
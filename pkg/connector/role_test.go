@@ -0,0 +1,91 @@
+package connector
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+)
+
+func TestIsGroupRoleRules(t *testing.T) {
+	ctx := context.Background()
+
+	// Neither rule below needs the role graph, so no client or cache is required.
+	r := &roleSyncer{
+		resourceType:      roleResourceType,
+		groupNoLoginRoles: true,
+		groupRolePattern:  regexp.MustCompile(`^analytics_`),
+	}
+
+	isGroup, err := r.isGroupRole(ctx, &postgres.RoleModel{Name: "readonly", CanLogin: false})
+	require.NoError(t, err)
+	require.True(t, isGroup)
+
+	isGroup, err = r.isGroupRole(ctx, &postgres.RoleModel{Name: "analytics_readonly", CanLogin: true})
+	require.NoError(t, err)
+	require.True(t, isGroup)
+}
+
+func TestRoleProfile(t *testing.T) {
+	validUntil := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	comment := "svc account for reporting"
+
+	profile := roleProfile(&postgres.RoleModel{
+		Name:            "reporting",
+		ConnectionLimit: 5,
+		ValidUntil:      &validUntil,
+		Comment:         &comment,
+		Settings: map[string][]string{
+			"*":        {"statement_timeout=30s"},
+			"postgres": {"work_mem=64MB", "search_path=reporting"},
+		},
+	})
+
+	require.Equal(t, 5, profile["connection_limit"])
+	require.Equal(t, "2030-01-02T03:04:05Z", profile["valid_until"])
+	require.Equal(t, comment, profile["comment"])
+	require.Equal(t, map[string]interface{}{
+		"*":        []interface{}{"statement_timeout=30s"},
+		"postgres": []interface{}{"work_mem=64MB", "search_path=reporting"},
+	}, profile["settings"])
+
+	// The profile has to be representable as a protobuf struct.
+	_, err := structpb.NewStruct(profile)
+	require.NoError(t, err)
+}
+
+func TestCreateUserOptions(t *testing.T) {
+	profile, err := structpb.NewStruct(map[string]interface{}{
+		"email":            "jane@example.com",
+		"groups":           []interface{}{"readonly", "analytics"},
+		"connection_limit": 10,
+		"valid_until":      "2030-01-02",
+		"no_password":      true,
+	})
+	require.NoError(t, err)
+
+	opts, noPassword, err := createUserOptions(profile)
+	require.NoError(t, err)
+	require.True(t, noPassword)
+	require.Equal(t, []string{"readonly", "analytics"}, opts.Groups)
+	require.NotNil(t, opts.ConnectionLimit)
+	require.Equal(t, 10, *opts.ConnectionLimit)
+	require.NotNil(t, opts.ValidUntil)
+	require.True(t, opts.ValidUntil.Equal(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	opts, noPassword, err = createUserOptions(nil)
+	require.NoError(t, err)
+	require.False(t, noPassword)
+	require.Nil(t, opts.ConnectionLimit)
+	require.Nil(t, opts.ValidUntil)
+
+	profile, err = structpb.NewStruct(map[string]interface{}{"valid_until": "next week"})
+	require.NoError(t, err)
+	_, _, err = createUserOptions(profile)
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/conductorone/baton-postgresql/pkg/postgres"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -19,14 +20,37 @@ var schemaResourceType = &v2.ResourceType{
 }
 
 type schemaSyncer struct {
-	resourceType *v2.ResourceType
-	clientPool   *postgres.ClientDatabasesPool
+	resourceType       *v2.ResourceType
+	clientPool         *postgres.ClientDatabasesPool
+	allowSchemaCascade bool
+	schemaOwner        string
+	cache              *syncCache
 }
 
 func (r *schemaSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
 	return schemaResourceType
 }
 
+func (r *schemaSyncer) makeResource(dbId int64, dbName string, parentResourceID *v2.ResourceId, schemaModel *postgres.SchemaModel) *v2.Resource {
+	var annos annotations.Annotations
+
+	annos.Append(&v2.ChildResourceType{ResourceTypeId: tableResourceType.Id})
+	annos.Append(&v2.ChildResourceType{ResourceTypeId: viewResourceType.Id})
+	annos.Append(&v2.ChildResourceType{ResourceTypeId: functionResourceType.Id})
+	annos.Append(&v2.ChildResourceType{ResourceTypeId: procedureResourceType.Id})
+	annos.Append(&v2.ChildResourceType{ResourceTypeId: sequenceResourceType.Id})
+
+	return &v2.Resource{
+		DisplayName: fmt.Sprintf("%s - %s", dbName, schemaModel.Name),
+		Id: &v2.ResourceId{
+			ResourceType: r.resourceType.Id,
+			Resource:     formatWithDatabaseID(r.resourceType.Id, strconv.FormatInt(dbId, 10), schemaModel.ID),
+		},
+		ParentResourceId: parentResourceID,
+		Annotations:      annos,
+	}
+}
+
 func (r *schemaSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var err error
 
@@ -59,23 +83,7 @@ func (r *schemaSyncer) List(ctx context.Context, parentResourceID *v2.ResourceId
 
 	var ret []*v2.Resource
 	for _, o := range schemas {
-		var annos annotations.Annotations
-
-		annos.Append(&v2.ChildResourceType{ResourceTypeId: tableResourceType.Id})
-		annos.Append(&v2.ChildResourceType{ResourceTypeId: viewResourceType.Id})
-		annos.Append(&v2.ChildResourceType{ResourceTypeId: functionResourceType.Id})
-		annos.Append(&v2.ChildResourceType{ResourceTypeId: procedureResourceType.Id})
-		annos.Append(&v2.ChildResourceType{ResourceTypeId: sequenceResourceType.Id})
-
-		ret = append(ret, &v2.Resource{
-			DisplayName: fmt.Sprintf("%s - %s", dbName, o.Name),
-			Id: &v2.ResourceId{
-				ResourceType: r.resourceType.Id,
-				Resource:     formatWithDatabaseID(r.resourceType.Id, strconv.FormatInt(dbId, 10), o.ID),
-			},
-			ParentResourceId: parentResourceID,
-			Annotations:      annos,
-		})
+		ret = append(ret, r.makeResource(dbId, dbName, parentResourceID, o))
 	}
 
 	return ret, nextPageToken, nil, nil
@@ -87,6 +95,8 @@ func (r *schemaSyncer) Entitlements(ctx context.Context, resource *v2.Resource,
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	return ens, "", nil, nil
 }
 
@@ -106,22 +116,183 @@ func (r *schemaSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
+	graph, err := r.cache.roleGraph(ctx, client)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, schema)
+	ret, err := roleGrantsForPrivileges(ctx, graph, resource, schema)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	ret = append(ret, ownerGrant(resource, schema))
+
+	return ret, "", nil, nil
+}
+
+func (r *schemaSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-postgres: only users and roles can have schema granted")
+	}
+
+	_, _, privilegeName, isGrant, err := parseEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbClient, _, err := r.clientPool.Get(ctx, dbId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema, err := dbClient.GetSchema(ctx, rID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetSchemaOwner(ctx, schema.Name, principal.DisplayName)
+		}
+		return dbClient.GrantSchema(ctx, schema.Name, principal.DisplayName, privilegeName, isGrant)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []*v2.Grant{
+		{
+			Id:          fmt.Sprintf("%s:%s:%s", entitlement.Id, principal.Id.ResourceType, principal.Id.Resource),
+			Entitlement: entitlement,
+			Principal:   principal,
+		},
+	}, nil, nil
+}
+
+func (r *schemaSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	entitlement := grant.Entitlement
+	principal := grant.Principal
+
+	if principal.Id.ResourceType != roleResourceType.Id {
+		return nil, fmt.Errorf("baton-postgres: only users and roles can have schema revoked")
+	}
+
+	_, _, privilegeName, isGrant, err := parseEntitlementID(entitlement.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
+	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	dbClient, _, err := r.clientPool.Get(ctx, dbId)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := dbClient.GetSchema(ctx, rID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dbClient.RevokeSchema(ctx, schema.Name, principal.DisplayName, privilegeName, isGrant)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
+}
+
+// Create creates the schema in the database given by the parent resource. It is owned by the configured schema
+// owner, or by the role the connector connects as when none is configured. The display name of the requested
+// resource is the bare schema name. Synced schemas are displayed as "<database> - <schema>" instead, so names
+// containing " - " are refused rather than creating a schema named after another schema's display name.
+func (r *schemaSyncer) Create(ctx context.Context, resource *v2.Resource) (*v2.Resource, annotations.Annotations, error) {
+	if resource.Id.ResourceType != schemaResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-postgres: non-schema resource passed to schema create")
+	}
+
+	schemaName := resource.GetDisplayName()
+	if strings.Contains(schemaName, " - ") {
+		return nil, nil, fmt.Errorf("baton-postgres: invalid schema name %q: pass the bare schema name, not the \"<database> - <schema>\" display name", schemaName)
+	}
+
+	parentResourceID := resource.GetParentResourceId()
+	if parentResourceID == nil || parentResourceID.ResourceType != databaseResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-postgres: schema create requires a parent database")
+	}
+
+	dbId, err := parseObjectID(parentResourceID.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, dbName, err := r.clientPool.Get(ctx, strconv.FormatInt(dbId, 10))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema, err := client.CreateSchema(ctx, schemaName, r.schemaOwner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.makeResource(dbId, dbName, parentResourceID, schema), nil, nil
+}
+
+func (r *schemaSyncer) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	if resourceId.ResourceType != schemaResourceType.Id {
+		return nil, fmt.Errorf("baton-postgres: non-schema resource passed to schema delete")
+	}
+
+	dbId, rID, err := parseWithDatabaseID(resourceId.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	client, _, err := r.clientPool.Get(ctx, dbId)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := client.GetSchema(ctx, rID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.allowSchemaCascade {
+		hasObjects, err := client.SchemaHasObjects(ctx, schema.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasObjects {
+			return nil, fmt.Errorf("baton-postgres: cannot delete schema '%s': schema is not empty and cascading deletes are not allowed", schema.Name)
+		}
+	}
+
+	err = client.DeleteSchema(ctx, schema.Name, r.allowSchemaCascade)
+	return nil, err
 }
 
-func newSchemaSyncer(ctx context.Context, c *postgres.ClientDatabasesPool) *schemaSyncer {
+func newSchemaSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, allowSchemaCascade bool, schemaOwner string, cache *syncCache) *schemaSyncer {
 	return &schemaSyncer{
-		resourceType: schemaResourceType,
-		clientPool:   c,
+		resourceType:       schemaResourceType,
+		clientPool:         c,
+		allowSchemaCascade: allowSchemaCascade,
+		schemaOwner:        schemaOwner,
+		cache:              cache,
 	}
 }
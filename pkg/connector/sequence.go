@@ -20,6 +20,7 @@ var sequenceResourceType = &v2.ResourceType{
 type sequenceSyncer struct {
 	resourceType *v2.ResourceType
 	clientPool   *postgres.ClientDatabasesPool
+	cache        *syncCache
 }
 
 func (r *sequenceSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -93,6 +94,8 @@ func (r *sequenceSyncer) Entitlements(ctx context.Context, resource *v2.Resource
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	for _, en := range ens {
 		en.DisplayName = fmt.Sprintf("%s on %s", dbModel.Name, en.DisplayName)
 	}
@@ -111,22 +114,14 @@ func (r *sequenceSyncer) Grants(ctx context.Context, resource *v2.Resource, pTok
 		return nil, "", nil, err
 	}
 
-	sequence, err := client.GetSequence(ctx, rID)
-	if err != nil {
-		return nil, "", nil, err
-	}
-
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
-	if err != nil {
-		return nil, "", nil, err
-	}
-
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, sequence)
+	ret, err := classGrants(ctx, r.cache, client, resource, rID, func() (postgres.ACLResource, error) {
+		return client.GetSequence(ctx, rID)
+	})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	return ret, "", nil, nil
 }
 
 func (r *sequenceSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
@@ -154,7 +149,12 @@ func (r *sequenceSyncer) Grant(ctx context.Context, principal *v2.Resource, enti
 		return nil, nil, err
 	}
 
-	err = dbClient.GrantSequence(ctx, sequence.Schema, sequence.Name, principal.DisplayName, privilegeName, isGrant)
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetSequenceOwner(ctx, sequence.Schema, sequence.Name, principal.DisplayName)
+		}
+		return dbClient.GrantSequence(ctx, sequence.Schema, sequence.Name, principal.DisplayName, privilegeName, isGrant)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -181,6 +181,10 @@ func (r *sequenceSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 		return nil, err
 	}
 
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
 	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
 	if err != nil {
 		return nil, err
@@ -197,12 +201,17 @@ func (r *sequenceSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 	}
 
 	err = dbClient.RevokeSequence(ctx, sequence.Schema, sequence.Name, principal.DisplayName, privilegeName, isGrant)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newSequenceSyncer(ctx context.Context, c *postgres.ClientDatabasesPool) *sequenceSyncer {
+func newSequenceSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, cache *syncCache) *sequenceSyncer {
 	return &sequenceSyncer{
 		resourceType: sequenceResourceType,
 		clientPool:   c,
+		cache:        cache,
 	}
 }
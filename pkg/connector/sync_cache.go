@@ -0,0 +1,133 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/conductorone/baton-postgresql/pkg/postgres"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types"
+)
+
+// syncCache holds catalog data that is loaded once per database and shared by the syncers for the rest of the
+// sync. It is reset when a sync starts and released when it finishes, so every sync sees the catalog as it is then.
+type syncCache struct {
+	mtx sync.Mutex
+	// values holds the loaded data by key: role graphs by database, and the tables, views and sequences of each
+	// schema by ID, by database and schema.
+	values map[string]interface{}
+	// generation is bumped by reset, so loads that started before it don't fill the cache of the next sync.
+	generation uint64
+	loads      singleflight.Group
+}
+
+func newSyncCache() *syncCache {
+	return &syncCache{
+		values: make(map[string]interface{}),
+	}
+}
+
+func (s *syncCache) reset() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.values = make(map[string]interface{})
+	s.generation++
+}
+
+// load returns the value cached under key, calling fn to load it on first use. Syncers asking for the same key at
+// the same time share one call to fn, and the cache isn't locked while it runs, so loads of other keys don't wait.
+func (s *syncCache) load(key string, fn func() (interface{}, error)) (interface{}, error) {
+	s.mtx.Lock()
+	v, ok := s.values[key]
+	generation := s.generation
+	s.mtx.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	v, err, _ := s.loads.Do(fmt.Sprintf("%d:%s", generation, key), func() (interface{}, error) {
+		s.mtx.Lock()
+		v, ok := s.values[key]
+		s.mtx.Unlock()
+		if ok {
+			return v, nil
+		}
+
+		v, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		if s.generation == generation {
+			s.values[key] = v
+		}
+
+		return v, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// roleGraph returns the role graph of the client's database, loading it on first use.
+func (s *syncCache) roleGraph(ctx context.Context, client *postgres.Client) (*postgres.RoleGraph, error) {
+	v, err := s.load("roles:"+client.DatabaseName(), func() (interface{}, error) {
+		return client.LoadRoleGraph(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*postgres.RoleGraph), nil
+}
+
+// schemaClass returns a table, view or sequence of a schema, loading the ACLs of the whole schema on first use. It
+// returns nil if the schema has no class with that ID, e.g. because it was created after the schema was loaded.
+func (s *syncCache) schemaClass(ctx context.Context, client *postgres.Client, schemaID int64, classID int64) (*postgres.ClassACLModel, error) {
+	v, err := s.load(fmt.Sprintf("classes:%s:%d", client.DatabaseName(), schemaID), func() (interface{}, error) {
+		list, err := client.ListSchemaClassACLs(ctx, schemaID)
+		if err != nil {
+			return nil, err
+		}
+
+		classes := make(map[int64]*postgres.ClassACLModel, len(list))
+		for _, class := range list {
+			classes[class.ID] = class
+		}
+
+		return classes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(map[int64]*postgres.ClassACLModel)[classID], nil
+}
+
+// cleanupServer releases the sync cache when the SDK cleans up after a sync.
+type cleanupServer struct {
+	types.ConnectorServer
+	cache *syncCache
+}
+
+func (s *cleanupServer) Cleanup(ctx context.Context, req *v2.ConnectorServiceCleanupRequest) (*v2.ConnectorServiceCleanupResponse, error) {
+	s.cache.reset()
+	return s.ConnectorServer.Cleanup(ctx, req)
+}
+
+// ReleaseCacheAfterSync wraps server, built from o, so that the catalog data cached during a sync is released when
+// the sync finishes rather than kept until the next one starts.
+func (o *Postgresql) ReleaseCacheAfterSync(server types.ConnectorServer) types.ConnectorServer {
+	return &cleanupServer{
+		ConnectorServer: server,
+		cache:           o.cache,
+	}
+}
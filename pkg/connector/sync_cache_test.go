@@ -0,0 +1,58 @@
+package connector
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCacheLoad(t *testing.T) {
+	cache := newSyncCache()
+
+	// Concurrent syncers asking for the same key share one load.
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.load("roles:db", func() (interface{}, error) {
+				calls.Add(1)
+				<-release
+				return "graph", nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "graph", v)
+		}()
+	}
+
+	// A load of another key doesn't wait for it.
+	v, err := cache.load("classes:db:1", func() (interface{}, error) {
+		return "classes", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "classes", v)
+
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+
+	v, err = cache.load("roles:db", func() (interface{}, error) {
+		require.FailNow(t, "loaded again")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "graph", v)
+
+	// After a reset, the next sync loads the catalog again.
+	cache.reset()
+	v, err = cache.load("roles:db", func() (interface{}, error) {
+		return "new graph", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "new graph", v)
+}
@@ -21,6 +21,7 @@ type tableSyncer struct {
 	resourceType   *v2.ResourceType
 	clientPool     *postgres.ClientDatabasesPool
 	includeColumns bool
+	cache          *syncCache
 }
 
 func (r *tableSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -103,6 +104,8 @@ func (r *tableSyncer) Entitlements(ctx context.Context, resource *v2.Resource, p
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	for _, en := range ens {
 		en.DisplayName = fmt.Sprintf("%s - %s", dbModel.Name, resource.DisplayName)
 	}
@@ -121,22 +124,14 @@ func (r *tableSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken
 		return nil, "", nil, err
 	}
 
-	table, err := client.GetTable(ctx, rID)
-	if err != nil {
-		return nil, "", nil, err
-	}
-
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
-	if err != nil {
-		return nil, "", nil, err
-	}
-
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, table)
+	ret, err := classGrants(ctx, r.cache, client, resource, rID, func() (postgres.ACLResource, error) {
+		return client.GetTable(ctx, rID)
+	})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	return ret, "", nil, nil
 }
 
 func (r *tableSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
@@ -164,7 +159,12 @@ func (r *tableSyncer) Grant(ctx context.Context, principal *v2.Resource, entitle
 		return nil, nil, err
 	}
 
-	err = dbClient.GrantTable(ctx, table.Schema, table.Name, principal.DisplayName, privilegeName, isGrant)
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetTableOwner(ctx, table.Schema, table.Name, principal.DisplayName)
+		}
+		return dbClient.GrantTable(ctx, table.Schema, table.Name, principal.DisplayName, privilegeName, isGrant)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -191,6 +191,10 @@ func (r *tableSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 		return nil, err
 	}
 
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
 	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
 	if err != nil {
 		return nil, err
@@ -207,13 +211,18 @@ func (r *tableSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 	}
 
 	err = dbClient.RevokeTable(ctx, table.Schema, table.Name, principal.DisplayName, privilegeName, isGrant)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newTableSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, includeColumns bool) *tableSyncer {
+func newTableSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, includeColumns bool, cache *syncCache) *tableSyncer {
 	return &tableSyncer{
 		resourceType:   tableResourceType,
 		clientPool:     c,
 		includeColumns: includeColumns,
+		cache:          cache,
 	}
 }
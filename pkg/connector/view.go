@@ -20,6 +20,7 @@ var viewResourceType = &v2.ResourceType{
 type viewSyncer struct {
 	resourceType *v2.ResourceType
 	clientPool   *postgres.ClientDatabasesPool
+	cache        *syncCache
 }
 
 func (r *viewSyncer) ResourceType(ctx context.Context) *v2.ResourceType {
@@ -93,6 +94,8 @@ func (r *viewSyncer) Entitlements(ctx context.Context, resource *v2.Resource, pT
 		return nil, "", nil, err
 	}
 
+	ens = append(ens, ownerEntitlement(resource))
+
 	for _, en := range ens {
 		en.DisplayName = fmt.Sprintf("%s on %s", dbModel.Name, en.DisplayName)
 	}
@@ -111,22 +114,14 @@ func (r *viewSyncer) Grants(ctx context.Context, resource *v2.Resource, pToken *
 		return nil, "", nil, err
 	}
 
-	view, err := client.GetView(ctx, rID)
-	if err != nil {
-		return nil, "", nil, err
-	}
-
-	roles, nextPageToken, err := client.ListRoles(ctx, &postgres.Pager{Token: pToken.Token, Size: pToken.Size})
-	if err != nil {
-		return nil, "", nil, err
-	}
-
-	ret, err := roleGrantsForPrivileges(ctx, client, resource, roles, view)
+	ret, err := classGrants(ctx, r.cache, client, resource, rID, func() (postgres.ACLResource, error) {
+		return client.GetView(ctx, rID)
+	})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	return ret, nextPageToken, nil, nil
+	return ret, "", nil, nil
 }
 
 func (r *viewSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) ([]*v2.Grant, annotations.Annotations, error) {
@@ -154,7 +149,12 @@ func (r *viewSyncer) Grant(ctx context.Context, principal *v2.Resource, entitlem
 		return nil, nil, err
 	}
 
-	err = dbClient.GrantView(ctx, view.Schema, view.Name, principal.DisplayName, privilegeName, isGrant)
+	err = grantWithExpiry(ctx, r.clientPool.Default(ctx), principal, entitlement, func() error {
+		if privilegeName == ownerSlug {
+			return dbClient.SetViewOwner(ctx, view.Schema, view.Name, principal.DisplayName)
+		}
+		return dbClient.GrantView(ctx, view.Schema, view.Name, principal.DisplayName, privilegeName, isGrant)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -181,6 +181,10 @@ func (r *viewSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.A
 		return nil, err
 	}
 
+	if privilegeName == ownerSlug {
+		return nil, errRevokeOwnership
+	}
+
 	dbId, rID, err := parseWithDatabaseID(entitlement.Resource.Id.Resource)
 	if err != nil {
 		return nil, err
@@ -197,12 +201,17 @@ func (r *viewSyncer) Revoke(ctx context.Context, grant *v2.Grant) (annotations.A
 	}
 
 	err = dbClient.RevokeView(ctx, view.Schema, view.Name, principal.DisplayName, privilegeName, isGrant)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, forgetGrantExpiry(ctx, r.clientPool.Default(ctx), grant)
 }
 
-func newViewSyncer(ctx context.Context, c *postgres.ClientDatabasesPool) *viewSyncer {
+func newViewSyncer(ctx context.Context, c *postgres.ClientDatabasesPool, cache *syncCache) *viewSyncer {
 	return &viewSyncer{
 		resourceType: viewResourceType,
 		clientPool:   c,
+		cache:        cache,
 	}
 }
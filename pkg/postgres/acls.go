@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jackc/pgconn"
+	"go.uber.org/zap"
+)
+
+// aclItemModel is a row of aclexplode(), selected as columns or, when exploding many ACLs at once, as JSON.
+type aclItemModel struct {
+	GranteeID     int64  `db:"grantee_id" json:"grantee_id"`
+	Grantee       string `db:"grantee" json:"grantee"`
+	GrantorID     int64  `db:"grantor_id" json:"grantor_id"`
+	Grantor       string `db:"grantor" json:"grantor"`
+	PrivilegeType string `db:"privilege_type" json:"privilege_type"`
+	IsGrantable   bool   `db:"is_grantable" json:"is_grantable"`
+}
+
+// explodedACL is embedded in the models of objects with an ACL. The queries that load a single object also select
+// its ACL exploded by aclexplode() into ACLItems, so decoding it doesn't take another query.
+type explodedACL struct {
+	ACLItems []*aclItemModel `db:"acl_items"`
+}
+
+func (e *explodedACL) aclItems() []*aclItemModel {
+	return e.ACLItems
+}
+
+// DecodeACL decodes the ACL of obj. When obj was loaded with its ACL exploded by the server, quoted role names are
+// handled and each entry has the OIDs of its grantee and grantor. Otherwise, e.g. on servers without aclexplode(),
+// the text form is parsed with NewACL.
+func DecodeACL(obj ACLResource) ([]*ACL, error) {
+	if e, ok := obj.(interface{ aclItems() []*aclItemModel }); ok {
+		if items := e.aclItems(); len(items) > 0 {
+			return aclsFromItems(items), nil
+		}
+	}
+
+	return parseACLs(obj.GetACLs())
+}
+
+// aclItemsColumn returns the select expression of the "acl_items" column, the ACL in aclColumn exploded as JSON so
+// that it fits in the row of its object. Unless exploded, it is an empty array and the ACL is parsed instead.
+func aclItemsColumn(aclColumn string, exploded bool) string {
+	if !exploded {
+		return `'[]'::jsonb AS "acl_items"`
+	}
+
+	return `COALESCE((SELECT jsonb_agg(jsonb_build_object(
+                        'grantee_id', acl."grantee"::int,
+                        'grantee', COALESCE(grantee."rolname", ''),
+                        'grantor_id', acl."grantor"::int,
+                        'grantor', COALESCE(grantor."rolname", ''),
+                        'privilege_type', acl."privilege_type",
+                        'is_grantable', acl."is_grantable"))
+                 FROM aclexplode(` + aclColumn + `) acl
+                          LEFT JOIN "pg_catalog"."pg_roles" grantee ON grantee."oid" = acl."grantee"
+                          LEFT JOIN "pg_catalog"."pg_roles" grantor ON grantor."oid" = acl."grantor"), '[]') AS "acl_items"`
+}
+
+// getWithACL runs q, which loads one object, into dst. q has a %s where it selects the "acl_items" column of the ACL
+// in aclColumn, which is exploded by the server when it has aclexplode().
+func (c *Client) getWithACL(ctx context.Context, dst interface{}, q string, aclColumn string, args ...interface{}) error {
+	exploded := !c.noACLExplode.Load()
+	err := pgxscan.Get(ctx, c.db, dst, fmt.Sprintf(q, aclItemsColumn(aclColumn, exploded)), args...)
+	if err != nil && exploded && c.aclExplodeUnsupported(ctx, err) {
+		err = pgxscan.Get(ctx, c.db, dst, fmt.Sprintf(q, aclItemsColumn(aclColumn, false)), args...)
+	}
+
+	return err
+}
+
+// aclExplodeUnsupported reports whether err means the server has no aclexplode(), and remembers it if so.
+func (c *Client) aclExplodeUnsupported(ctx context.Context, err error) bool {
+	var pgErr *pgconn.PgError
+	// undefined_function, raised for aclexplode() itself rather than anything else in the query.
+	if !errors.As(err, &pgErr) || pgErr.Code != "42883" || !strings.Contains(pgErr.Message, "aclexplode") {
+		return false
+	}
+
+	ctxzap.Extract(ctx).Info("aclexplode() is not available, parsing ACLs instead", zap.Error(err))
+	c.noACLExplode.Store(true)
+
+	return true
+}
+
+// aclsFromItems puts the privileges of each grantee and grantor pair back together, as aclexplode() returns a row
+// per privilege.
+func aclsFromItems(items []*aclItemModel) []*ACL {
+	type aclKey struct {
+		grantee int64
+		grantor int64
+	}
+	var ret []*ACL
+	byKey := make(map[aclKey]*ACL)
+	for _, item := range items {
+		k := aclKey{item.GranteeID, item.GrantorID}
+		acl, ok := byKey[k]
+		if !ok {
+			acl = &ACL{
+				grantee:   item.Grantee,
+				grantor:   item.Grantor,
+				resolved:  true,
+				granteeID: item.GranteeID,
+				grantorID: item.GrantorID,
+			}
+			byKey[k] = acl
+			ret = append(ret, acl)
+		}
+
+		priv := PrivilegeSetFromName(item.PrivilegeType)
+		acl.privs = acl.privs.Set(priv)
+		if item.IsGrantable {
+			acl.privsWithGrant = acl.privsWithGrant.Set(priv)
+		}
+	}
+
+	return ret
+}
+
+func parseACLs(acls []string) ([]*ACL, error) {
+	ret := make([]*ACL, 0, len(acls))
+	for _, pgACL := range acls {
+		acl, err := NewACL(pgACL)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, acl)
+	}
+
+	return ret, nil
+}
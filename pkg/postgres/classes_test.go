@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestClassACLModelObject(t *testing.T) {
+	class := &ClassACLModel{ID: 1, Name: "orders", Schema: "public", OwnerID: 10, ACLs: []string{"foo=r/bar"}}
+
+	class.Kind = "r"
+	require.Equal(t, &TableModel{ID: 1, Name: "orders", Schema: "public", OwnerID: 10, ACLs: []string{"foo=r/bar"}}, class.Object())
+	class.Kind = "p"
+	require.IsType(t, &TableModel{}, class.Object())
+	class.Kind = "v"
+	require.IsType(t, &ViewModel{}, class.Object())
+	class.Kind = "S"
+	require.IsType(t, &SequenceModel{}, class.Object())
+}
+
+func TestListSchemaClassACLs(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	err = client.GrantTable(ctx, "public", "test_table", container.Role(), Select.Name(), true)
+	require.NoError(t, err)
+
+	var schemaID int64
+	err = client.db.QueryRow(ctx, `SELECT 'public'::regnamespace::oid::int`).Scan(&schemaID)
+	require.NoError(t, err)
+
+	roleIDs, err := client.GetRoleIDsByName(ctx, []string{container.Role()})
+	require.NoError(t, err)
+
+	for _, exploded := range []bool{true, false} {
+		client.noACLExplode.Store(!exploded)
+
+		classes, err := client.ListSchemaClassACLs(ctx, schemaID)
+		require.NoError(t, err)
+
+		byName := make(map[string]*ClassACLModel)
+		for _, class := range classes {
+			byName[class.Name] = class
+		}
+
+		require.Contains(t, byName, "test_table_view")
+		require.Equal(t, "v", byName["test_table_view"].Kind)
+
+		table := byName["test_table"]
+		require.NotNil(t, table)
+		require.Equal(t, "r", table.Kind)
+
+		var found bool
+		for _, acl := range table.DecodedACLs() {
+			if acl.Grantee() != container.Role() {
+				continue
+			}
+			found = true
+			require.Equal(t, Select, acl.Privileges())
+			require.Equal(t, Select, acl.GrantPrivileges())
+
+			id, ok := acl.GranteeID()
+			require.Equal(t, exploded, ok)
+			if ok {
+				require.Equal(t, roleIDs[container.Role()], id)
+			}
+		}
+		require.True(t, found)
+	}
+}
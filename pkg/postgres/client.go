@@ -2,9 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"go.uber.org/zap"
 )
@@ -95,10 +100,42 @@ func (p *ClientDatabasesPool) Get(ctx context.Context, database string) (*Client
 	return c, dbModel.Name, nil
 }
 
+// ForEachDatabase calls f with a client for every database that accepts connections. A database that fails doesn't
+// stop the others from being visited, and the errors of every failed database are returned joined.
+func (p *ClientDatabasesPool) ForEachDatabase(ctx context.Context, f func(c *Client, dbName string) error) error {
+	databases, err := p.defaultClientDsn.ListConnectableDatabases(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ret error
+	for _, db := range databases {
+		c, dbName, err := p.Get(ctx, strconv.FormatInt(db.ID, 10))
+		if err != nil {
+			ret = errors.Join(ret, fmt.Errorf("error connecting to database %s: %w", db.Name, err))
+			continue
+		}
+
+		err = f(c, dbName)
+		if err != nil {
+			ret = errors.Join(ret, err)
+		}
+	}
+
+	return ret
+}
+
 type Client struct {
-	db           *pgxpool.Pool
-	cfg          *pgxpool.Config
-	schemaFilter []string
+	db             *pgxpool.Pool
+	cfg            *pgxpool.Config
+	schemaFilter   []string
+	protectedRoles map[string]bool
+
+	versionMtx       sync.Mutex
+	serverVersionNum int
+
+	// noACLExplode is set once the server turns out not to have aclexplode().
+	noACLExplode atomic.Bool
 }
 
 func (c *Client) ValidateConnection(ctx context.Context) error {
@@ -150,3 +187,42 @@ func New(ctx context.Context, dsn string, opts ...ClientOpt) (*Client, error) {
 func (c *Client) DatabaseName() string {
 	return c.cfg.ConnConfig.Database
 }
+
+func (c *Client) Host() string {
+	return c.cfg.ConnConfig.Host
+}
+
+func (c *Client) Port() uint16 {
+	return c.cfg.ConnConfig.Port
+}
+
+// ServerVersionNum returns the server version in its numeric form, e.g. 160002 for 16.2.
+// The version is looked up once and cached for the lifetime of the client.
+func (c *Client) ServerVersionNum(ctx context.Context) (int, error) {
+	c.versionMtx.Lock()
+	defer c.versionMtx.Unlock()
+
+	if c.serverVersionNum != 0 {
+		return c.serverVersionNum, nil
+	}
+
+	var versionNum int
+	err := c.db.QueryRow(ctx, "SELECT current_setting('server_version_num')::int").Scan(&versionNum)
+	if err != nil {
+		return 0, err
+	}
+	c.serverVersionNum = versionNum
+
+	return versionNum, nil
+}
+
+// alterOwner transfers ownership of an object. The object name must already be sanitized.
+func (c *Client) alterOwner(ctx context.Context, objectType string, sanitizedObjectName string, ownerName string) error {
+	l := ctxzap.Extract(ctx)
+
+	q := fmt.Sprintf("ALTER %s %s OWNER TO %s", objectType, sanitizedObjectName, pgx.Identifier{ownerName}.Sanitize())
+	l.Debug("changing owner", zap.String("query", q))
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
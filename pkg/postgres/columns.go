@@ -4,11 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"strconv"
+	"fmt"
 	"strings"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jackc/pgx/v4"
 	"go.uber.org/zap"
 )
 
@@ -16,8 +17,11 @@ type ColumnModel struct {
 	ID        int64    `db:"attnum"`
 	Name      string   `db:"attname"`
 	TableName string   `db:"tablename"`
+	Schema    string   `db:"nspname"`
 	OwnerID   int64    `db:"relowner"`
 	ACLs      []string `db:"attacl"`
+
+	explodedACL
 }
 
 func (t *ColumnModel) GetOwnerID() int64 {
@@ -43,14 +47,18 @@ func (c *Client) GetColumn(ctx context.Context, tableID int64, columnID int64) (
 SELECT a."attnum",
        a."attname",
        a."attacl",
-       c."relowner"
+       c."relowner",
+       c."relname" AS "tablename",
+       n."nspname",
+       %s
 FROM "pg_catalog"."pg_attribute" a
          LEFT JOIN "pg_catalog"."pg_class" c ON c."oid" = a."attrelid"
+         LEFT JOIN "pg_catalog"."pg_namespace" n ON n."oid" = c."relnamespace"
 WHERE "attrelid" = $1
   AND "attnum" = $2
 `
 
-	err := pgxscan.Get(ctx, c.db, ret, q, tableID, columnID)
+	err := c.getWithACL(ctx, ret, q, `a."attacl"`, tableID, columnID)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +70,7 @@ func (c *Client) ListColumns(ctx context.Context, tableID int64, pager *Pager) (
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing columns for table", zap.Int64("table_id", tableID))
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -72,21 +80,22 @@ func (c *Client) ListColumns(ctx context.Context, tableID int64, pager *Pager) (
 SELECT a."attnum",
        a."attname",
        a."attacl",
-       c."relowner"
+       c."relowner",
+       c."relname" AS "tablename",
+       n."nspname"
 FROM "pg_catalog"."pg_attribute" a
          LEFT JOIN "pg_catalog"."pg_class" c ON c."oid" = a."attrelid"
+         LEFT JOIN "pg_catalog"."pg_namespace" n ON n."oid" = c."relnamespace"
 WHERE a."attrelid" = $1
   AND a."attnum" > 0
   AND NOT a."attisdropped"
 `)
 
 	args = append(args, tableID)
-	_, _ = sb.WriteString("LIMIT $2 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $3")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`  AND a."attnum" > $2::int
+ORDER BY a."attnum"
+LIMIT $3`)
+	args = append(args, after, limit+1)
 
 	var ret []*ColumnModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -99,10 +108,55 @@ WHERE a."attrelid" = $1
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(ret[limit-1].ID)
 	}
 
 	return ret, nextPageToken, nil
 }
+
+func (c *Client) GrantColumn(ctx context.Context, schema string, tableName string, columnName string, principalName string, privilege string, isGrant bool) error {
+	l := ctxzap.Extract(ctx)
+	l.Debug("granting column", zap.String("columnName", columnName), zap.String("principalName", principalName), zap.String("privilege", privilege))
+
+	sanitizedSchema := pgx.Identifier{schema}.Sanitize()
+	sanitizedTableName := pgx.Identifier{tableName}.Sanitize()
+	sanitizedColumnName := pgx.Identifier{columnName}.Sanitize()
+	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
+	sanitizedPrivilege := sanitizePrivilege(privilege)
+
+	q := fmt.Sprintf("GRANT %s (%s) ON TABLE %s.%s TO %s", sanitizedPrivilege, sanitizedColumnName, sanitizedSchema, sanitizedTableName, sanitizedPrincipalName)
+
+	if isGrant {
+		q += withGrantOptions
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
+
+func (c *Client) RevokeColumn(ctx context.Context, schema string, tableName string, columnName string, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
+	l := ctxzap.Extract(ctx)
+	l.Debug("revoking column", zap.String("columnName", columnName), zap.String("principalName", principalName), zap.String("privilege", privilege))
+
+	sanitizedSchema := pgx.Identifier{schema}.Sanitize()
+	sanitizedTableName := pgx.Identifier{tableName}.Sanitize()
+	sanitizedColumnName := pgx.Identifier{columnName}.Sanitize()
+	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
+	sanitizedPrivilege := sanitizePrivilege(privilege)
+
+	var q string
+
+	if isGrant {
+		q = fmt.Sprintf("REVOKE GRANT OPTION FOR %s (%s) ON TABLE %s.%s FROM %s", sanitizedPrivilege, sanitizedColumnName, sanitizedSchema, sanitizedTableName, sanitizedPrincipalName)
+	} else {
+		q = fmt.Sprintf("REVOKE %s (%s) ON TABLE %s.%s FROM %s", sanitizedPrivilege, sanitizedColumnName, sanitizedSchema, sanitizedTableName, sanitizedPrincipalName)
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/georgysavva/scany/pgxscan"
@@ -19,6 +18,8 @@ type DatabaseModel struct {
 	Name    string   `db:"datname"`
 	OwnerID int64    `db:"datdba"`
 	ACLs    []string `db:"datacl"`
+
+	explodedACL
 }
 
 func (t *DatabaseModel) GetOwnerID() int64 {
@@ -44,12 +45,13 @@ func (c *Client) GetDatabase(ctx context.Context, dbID int64) (*DatabaseModel, e
 SELECT "oid"::int,
        "datname",
        "datdba",
-       "datacl"
+       "datacl",
+       %s
 from "pg_catalog"."pg_database"
 WHERE "oid"=$1
 `
 
-	err := pgxscan.Get(ctx, c.db, ret, q, dbID)
+	err := c.getWithACL(ctx, ret, q, `"datacl"`, dbID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +103,7 @@ func (c *Client) ListDatabases(ctx context.Context, pager *Pager) ([]*DatabaseMo
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing databases")
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -114,12 +116,10 @@ SELECT "oid"::int,
        "datacl"
 from "pg_catalog"."pg_database"
 `)
-	_, _ = sb.WriteString("LIMIT $1 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $2")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`WHERE "oid" > $1::bigint::oid
+ORDER BY "oid"
+LIMIT $2`)
+	args = append(args, after, limit+1)
 
 	var ret []*DatabaseModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -132,14 +132,35 @@ from "pg_catalog"."pg_database"
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
 }
 
+// ListConnectableDatabases returns every non-template database that accepts connections.
+func (c *Client) ListConnectableDatabases(ctx context.Context) ([]*DatabaseModel, error) {
+	q := `
+SELECT "oid"::int,
+       "datname",
+       "datdba",
+       "datacl"
+from "pg_catalog"."pg_database"
+WHERE "datallowconn"
+  AND NOT "datistemplate"
+ORDER BY "datname"
+`
+
+	var ret []*DatabaseModel
+	err := pgxscan.Select(ctx, c.db, &ret, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (c *Client) CreateDatabase(ctx context.Context, dbName string) (*DatabaseModel, error) {
 	l := ctxzap.Extract(ctx)
 	l.Debug("creating database", zap.String("dbName", dbName))
@@ -196,6 +217,10 @@ func (c *Client) GrantDatabase(ctx context.Context, dbName string, principalName
 }
 
 func (c *Client) RevokeDatabase(ctx context.Context, dbName string, target string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(target); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 
 	sanitizedDbName := pgx.Identifier{dbName}.Sanitize()
@@ -212,3 +237,7 @@ func (c *Client) RevokeDatabase(ctx context.Context, dbName string, target strin
 	_, err := c.db.Exec(ctx, q)
 	return err
 }
+
+func (c *Client) SetDatabaseOwner(ctx context.Context, dbName string, ownerName string) error {
+	return c.alterOwner(ctx, "DATABASE", pgx.Identifier{dbName}.Sanitize(), ownerName)
+}
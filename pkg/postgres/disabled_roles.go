@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+)
+
+// DisabledRoleModel is a role the connector disabled, kept in baton.disabled_roles so that enabling it restores what
+// disabling it changed and nothing else. ValidUntil is the role's VALID UNTIL before it was expired, nil if it never
+// expired, and is only restored when Expired is set.
+type DisabledRoleModel struct {
+	RoleID     int64      `db:"role_id"`
+	RoleName   string     `db:"role_name"`
+	CouldLogin bool       `db:"could_login"`
+	Expired    bool       `db:"expired"`
+	ValidUntil *time.Time `db:"valid_until"`
+	DisabledAt time.Time  `db:"disabled_at"`
+}
+
+// disabledRolesTableExists reports whether the bookkeeping table has been created. Like baton.grant_expirations, it
+// is only created when the first role is disabled.
+func (c *Client) disabledRolesTableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	err := c.db.QueryRow(ctx, `SELECT to_regclass('"baton"."disabled_roles"') IS NOT NULL`).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (c *Client) ensureDisabledRolesTable(ctx context.Context) error {
+	l := ctxzap.Extract(ctx)
+	l.Debug("creating disabled roles table")
+
+	_, err := c.db.Exec(ctx, `CREATE SCHEMA IF NOT EXISTS "baton"`)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.db.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS "baton"."disabled_roles"
+(
+    "role_id"     bigint      NOT NULL PRIMARY KEY,
+    "role_name"   text        NOT NULL,
+    "could_login" boolean     NOT NULL,
+    "expired"     boolean     NOT NULL,
+    "valid_until" timestamptz,
+    "disabled_at" timestamptz NOT NULL DEFAULT now()
+)`)
+	return err
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v4"
@@ -23,6 +22,8 @@ type FunctionModel struct {
 	ACLs       []string `db:"proacl"`
 	Arguments  string   `db:"arguments"`
 	ReturnType string   `db:"return_type"`
+
+	explodedACL
 }
 
 func (t *FunctionModel) GetOwnerID() int64 {
@@ -55,13 +56,14 @@ SELECT DISTINCT
        n."nspname",
        a."proowner"::int, a."proacl",
 	   pg_get_function_arguments(a.oid) AS arguments,
-       pg_get_function_result(a.oid) AS return_type
+       pg_get_function_result(a.oid) AS return_type,
+       %s
 FROM "pg_catalog"."pg_proc" a
          LEFT JOIN pg_namespace n ON n."oid" = a."pronamespace"
 WHERE a."oid" = $1
 `
 
-	err := pgxscan.Get(ctx, c.db, ret, q, functionID)
+	err := c.getWithACL(ctx, ret, q, `a."proacl"`, functionID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +75,7 @@ func (c *Client) ListFunctions(ctx context.Context, schemaID int64, skipBuiltInF
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing functions for schema", zap.Int64("schema_id", schemaID))
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -97,12 +99,10 @@ WHERE a."prokind" = 'f'
 	}
 
 	args = append(args, schemaID)
-	_, _ = sb.WriteString("LIMIT $2 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $3")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`  AND a."oid" > $2::bigint::oid
+ORDER BY a."oid"
+LIMIT $3`)
+	args = append(args, after, limit+1)
 
 	var ret []*FunctionModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -115,9 +115,8 @@ WHERE a."prokind" = 'f'
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
@@ -143,6 +142,10 @@ func (c *Client) GrantFunction(ctx context.Context, schema string, functionSigna
 }
 
 func (c *Client) RevokeFunction(ctx context.Context, schema string, functionSignature *FunctionModel, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 	l.Debug("revoking function", zap.String("principalName", principalName), zap.String("privilege", privilege))
 
@@ -161,3 +164,7 @@ func (c *Client) RevokeFunction(ctx context.Context, schema string, functionSign
 	_, err := c.db.Exec(ctx, q)
 	return err
 }
+
+func (c *Client) SetFunctionOwner(ctx context.Context, schema string, function *FunctionModel, ownerName string) error {
+	return c.alterOwner(ctx, "FUNCTION", pgx.Identifier{schema}.Sanitize()+"."+function.Signature(), ownerName)
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// GrantExpirationModel is a time-bound grant made by the connector, kept in baton.grant_expirations so that it can
+// be revoked once it expires. PrincipalName is the principal's name when the grant was made, kept for the logs; the
+// role may since have been renamed, so it is looked up by PrincipalID when the grant is revoked.
+type GrantExpirationModel struct {
+	EntitlementID string    `db:"entitlement_id"`
+	ResourceType  string    `db:"resource_type"`
+	ResourceID    string    `db:"resource_id"`
+	PrincipalType string    `db:"principal_type"`
+	PrincipalID   string    `db:"principal_id"`
+	PrincipalName string    `db:"principal_name"`
+	ExpiresAt     time.Time `db:"expires_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// grantExpirationsTableExists reports whether the bookkeeping table has been created. It is only created when the
+// first time-bound grant is made, so connectors that never make one don't need CREATE on the database.
+func (c *Client) grantExpirationsTableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	err := c.db.QueryRow(ctx, `SELECT to_regclass('"baton"."grant_expirations"') IS NOT NULL`).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (c *Client) ensureGrantExpirationsTable(ctx context.Context) error {
+	l := ctxzap.Extract(ctx)
+	l.Debug("creating grant expirations table")
+
+	_, err := c.db.Exec(ctx, `CREATE SCHEMA IF NOT EXISTS "baton"`)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.db.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS "baton"."grant_expirations"
+(
+    "entitlement_id" text        NOT NULL,
+    "resource_type"  text        NOT NULL,
+    "resource_id"    text        NOT NULL,
+    "principal_type" text        NOT NULL,
+    "principal_id"   text        NOT NULL,
+    "principal_name" text        NOT NULL,
+    "expires_at"     timestamptz NOT NULL,
+    "created_at"     timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY ("entitlement_id", "principal_id")
+)`)
+	return err
+}
+
+// SetGrantExpiration records when a grant expires, replacing the expiry of an earlier grant of the same
+// entitlement to the same principal.
+func (c *Client) SetGrantExpiration(ctx context.Context, expiration *GrantExpirationModel) error {
+	l := ctxzap.Extract(ctx)
+
+	err := c.ensureGrantExpirationsTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.Debug(
+		"recording grant expiration",
+		zap.String("entitlement_id", expiration.EntitlementID),
+		zap.String("principal_id", expiration.PrincipalID),
+		zap.Time("expires_at", expiration.ExpiresAt),
+	)
+
+	_, err = c.db.Exec(ctx, `
+INSERT INTO "baton"."grant_expirations"
+    ("entitlement_id", "resource_type", "resource_id", "principal_type", "principal_id", "principal_name", "expires_at")
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT ("entitlement_id", "principal_id") DO UPDATE
+    SET "resource_type"  = EXCLUDED."resource_type",
+        "resource_id"    = EXCLUDED."resource_id",
+        "principal_type" = EXCLUDED."principal_type",
+        "principal_name" = EXCLUDED."principal_name",
+        "expires_at"     = EXCLUDED."expires_at",
+        "created_at"     = now()
+`,
+		expiration.EntitlementID,
+		expiration.ResourceType,
+		expiration.ResourceID,
+		expiration.PrincipalType,
+		expiration.PrincipalID,
+		expiration.PrincipalName,
+		expiration.ExpiresAt,
+	)
+	return err
+}
+
+// GetGrantExpiration returns the recorded expiry of a grant, or nil if the grant isn't time-bound.
+func (c *Client) GetGrantExpiration(ctx context.Context, entitlementID string, principalID string) (*GrantExpirationModel, error) {
+	exists, err := c.grantExpirationsTableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	q := `
+SELECT "entitlement_id",
+       "resource_type",
+       "resource_id",
+       "principal_type",
+       "principal_id",
+       "principal_name",
+       "expires_at",
+       "created_at"
+FROM "baton"."grant_expirations"
+WHERE "entitlement_id" = $1
+  AND "principal_id" = $2
+`
+
+	ret := &GrantExpirationModel{}
+	err = pgxscan.Get(ctx, c.db, ret, q, entitlementID, principalID)
+	if err != nil {
+		if pgxscan.NotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// DeleteGrantExpiration forgets the expiry of a grant, e.g. because it was revoked or granted again without one.
+func (c *Client) DeleteGrantExpiration(ctx context.Context, entitlementID string, principalID string) error {
+	exists, err := c.grantExpirationsTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	_, err = c.db.Exec(
+		ctx,
+		`DELETE FROM "baton"."grant_expirations" WHERE "entitlement_id" = $1 AND "principal_id" = $2`,
+		entitlementID,
+		principalID,
+	)
+	return err
+}
+
+// ListExpiredGrants returns the recorded grants that expired before now.
+func (c *Client) ListExpiredGrants(ctx context.Context, now time.Time) ([]*GrantExpirationModel, error) {
+	exists, err := c.grantExpirationsTableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	q := `
+SELECT "entitlement_id",
+       "resource_type",
+       "resource_id",
+       "principal_type",
+       "principal_id",
+       "principal_name",
+       "expires_at",
+       "created_at"
+FROM "baton"."grant_expirations"
+WHERE "expires_at" <= $1
+ORDER BY "expires_at"
+`
+
+	var ret []*GrantExpirationModel
+	err = pgxscan.Select(ctx, c.db, &ret, q, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestGrantExpirations(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	// Nothing has been recorded yet, so the table doesn't exist.
+	expired, err := client.ListExpiredGrants(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	err = client.DeleteGrantExpiration(ctx, "entitlement:role:role:1:member", "role:2")
+	require.NoError(t, err)
+
+	recorded, err := client.GetGrantExpiration(ctx, "entitlement:role:role:1:member", "role:2")
+	require.NoError(t, err)
+	require.Nil(t, recorded)
+
+	expiration := &GrantExpirationModel{
+		EntitlementID: "entitlement:role:role:1:member",
+		ResourceType:  "role",
+		ResourceID:    "role:1",
+		PrincipalType: "role",
+		PrincipalID:   "role:2",
+		PrincipalName: "test_user",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+	err = client.SetGrantExpiration(ctx, expiration)
+	require.NoError(t, err)
+
+	recorded, err = client.GetGrantExpiration(ctx, expiration.EntitlementID, expiration.PrincipalID)
+	require.NoError(t, err)
+	require.NotNil(t, recorded)
+	require.Equal(t, "role:1", recorded.ResourceID)
+
+	expired, err = client.ListExpiredGrants(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	expired, err = client.ListExpiredGrants(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "test_user", expired[0].PrincipalName)
+
+	// Granting again replaces the expiry instead of adding a second row.
+	expiration.ExpiresAt = time.Now().Add(3 * time.Hour)
+	err = client.SetGrantExpiration(ctx, expiration)
+	require.NoError(t, err)
+
+	expired, err = client.ListExpiredGrants(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	err = client.DeleteGrantExpiration(ctx, expiration.EntitlementID, expiration.PrincipalID)
+	require.NoError(t, err)
+
+	expired, err = client.ListExpiredGrants(ctx, time.Now().Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, expired)
+}
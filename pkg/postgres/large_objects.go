@@ -4,17 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
 )
 
 type LargeObjectModel struct {
 	ID      int64    `db:"oid"`
 	OwnerID int64    `db:"lomowner"`
 	ACLs    []string `db:"lomacl"`
+
+	explodedACL
 }
 
 func (t *LargeObjectModel) GetOwnerID() int64 {
@@ -37,7 +42,7 @@ func (c *Client) ListLargeObjects(ctx context.Context, pager *Pager) ([]*LargeOb
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing large objects")
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -49,12 +54,10 @@ SELECT "oid"::int,
        "lomacl"
 from "pg_catalog"."pg_largeobject_metadata"
 `)
-	_, _ = sb.WriteString("LIMIT $1 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $2")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`WHERE "oid" > $1::bigint::oid
+ORDER BY "oid"
+LIMIT $2`)
+	args = append(args, after, limit+1)
 
 	var ret []*LargeObjectModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -67,9 +70,8 @@ from "pg_catalog"."pg_largeobject_metadata"
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
@@ -83,16 +85,61 @@ func (c *Client) GetLargeObject(ctx context.Context, largeObjectID int64) (*Larg
 	_, _ = sb.WriteString(`
 SELECT "oid"::int,
        "lomowner",
-       "lomacl"
+       "lomacl",
+       %s
 from "pg_catalog"."pg_largeobject_metadata"
 WHERE oid=$1
 `)
 
 	var ret LargeObjectModel
-	err := pgxscan.Get(ctx, c.db, &ret, sb.String(), largeObjectID)
+	err := c.getWithACL(ctx, &ret, sb.String(), `"lomacl"`, largeObjectID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ret, nil
 }
+
+func (c *Client) GrantLargeObject(ctx context.Context, largeObjectID int64, principalName string, privilege string, isGrant bool) error {
+	l := ctxzap.Extract(ctx)
+	l.Debug("granting large object", zap.Int64("largeObjectID", largeObjectID), zap.String("principalName", principalName), zap.String("privilege", privilege))
+
+	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
+	sanitizedPrivilege := sanitizePrivilege(privilege)
+
+	q := fmt.Sprintf("GRANT %s ON LARGE OBJECT %d TO %s", sanitizedPrivilege, largeObjectID, sanitizedPrincipalName)
+
+	if isGrant {
+		q += withGrantOptions
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
+
+func (c *Client) RevokeLargeObject(ctx context.Context, largeObjectID int64, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
+	l := ctxzap.Extract(ctx)
+	l.Debug("revoking large object", zap.Int64("largeObjectID", largeObjectID), zap.String("principalName", principalName), zap.String("privilege", privilege))
+
+	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
+	sanitizedPrivilege := sanitizePrivilege(privilege)
+
+	var q string
+
+	if isGrant {
+		q = fmt.Sprintf("REVOKE GRANT OPTION FOR %s ON LARGE OBJECT %d FROM %s", sanitizedPrivilege, largeObjectID, sanitizedPrincipalName)
+	} else {
+		q = fmt.Sprintf("REVOKE %s ON LARGE OBJECT %d FROM %s", sanitizedPrivilege, largeObjectID, sanitizedPrincipalName)
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
+
+func (c *Client) SetLargeObjectOwner(ctx context.Context, largeObjectID int64, ownerName string) error {
+	return c.alterOwner(ctx, "LARGE OBJECT", strconv.FormatInt(largeObjectID, 10), ownerName)
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestLargeObjectGrantRevoke(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	var largeObjectID int64
+	err = container.Db().QueryRow(ctx, "SELECT lo_create(0)::int").Scan(&largeObjectID)
+	require.NoError(t, err)
+
+	// Is grant true
+	err = client.GrantLargeObject(ctx, largeObjectID, container.Role(), Select.Name(), true)
+	require.NoError(t, err)
+
+	err = client.RevokeLargeObject(ctx, largeObjectID, container.Role(), Select.Name(), true)
+	require.NoError(t, err)
+
+	// is grant false
+	err = client.GrantLargeObject(ctx, largeObjectID, container.Role(), Update.Name(), false)
+	require.NoError(t, err)
+
+	err = client.RevokeLargeObject(ctx, largeObjectID, container.Role(), Update.Name(), false)
+	require.NoError(t, err)
+}
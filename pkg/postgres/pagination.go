@@ -1,7 +1,11 @@
 package postgres
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -9,27 +13,35 @@ const (
 	MinPageSize = 100
 )
 
+// keysetTokenVersion prefixes keyset page tokens, so the format can change without old tokens being misread.
+const keysetTokenVersion = "k1"
+
+// ErrInvalidPageToken is returned for page tokens that weren't made by this version of the connector, such as the
+// offsets earlier versions used for catalog listings. A sync holding one has to start over from the first page.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
 type Pager struct {
 	Token string
 	Size  int
 }
 
-// Parse returns the offset and page size.
-func (p *Pager) Parse() (int, int, error) {
-	var parsedPageSize int
-	var offset int
-	var err error
+func (p *Pager) pageSize() int {
 	switch {
 	case p.Size <= MinPageSize:
-		parsedPageSize = MinPageSize
+		return MinPageSize
 
 	case p.Size > MaxPageSize:
-		parsedPageSize = MaxPageSize
+		return MaxPageSize
 
 	default:
-		parsedPageSize = p.Size
+		return p.Size
 	}
+}
 
+// Parse returns the offset and page size.
+func (p *Pager) Parse() (int, int, error) {
+	var offset int
+	var err error
 	if p.Token != "" {
 		offset, err = strconv.Atoi(p.Token)
 		if err != nil {
@@ -37,5 +49,45 @@ func (p *Pager) Parse() (int, int, error) {
 		}
 	}
 
-	return offset, parsedPageSize, nil
+	return offset, p.pageSize(), nil
+}
+
+// ParseKeyset returns the key the page starts after and the page size, for listings ordered by a unique key such as
+// the oid. The key is 0 for the first page.
+func (p *Pager) ParseKeyset() (int64, int, error) {
+	if p.Token == "" {
+		return 0, p.pageSize(), nil
+	}
+
+	if _, err := strconv.Atoi(p.Token); err == nil {
+		return 0, 0, fmt.Errorf("%w: offset tokens are no longer supported", ErrInvalidPageToken)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(p.Token)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+	}
+
+	version, key, ok := strings.Cut(string(raw), ":")
+	if !ok || version != keysetTokenVersion {
+		return 0, 0, fmt.Errorf("%w: unknown token version", ErrInvalidPageToken)
+	}
+
+	after, err := strconv.ParseInt(key, 10, 64)
+	if err != nil || after < 0 {
+		return 0, 0, fmt.Errorf("%w: malformed key", ErrInvalidPageToken)
+	}
+
+	return after, p.pageSize(), nil
+}
+
+// keysetPageToken returns the token of the page that starts after key.
+func keysetPageToken(key int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(keysetTokenVersion + ":" + strconv.FormatInt(key, 10)))
+}
+
+// oidKey returns the keyset key of an oid selected as "oid"::int. The cast wraps oids above 2^31 to negative
+// numbers, so turn them back into the unsigned value the catalog orders by.
+func oidKey(id int64) int64 {
+	return int64(uint32(id))
 }
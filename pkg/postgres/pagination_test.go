@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagerParseKeyset(t *testing.T) {
+	after, size, err := (&Pager{Size: 50}).ParseKeyset()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), after)
+	require.Equal(t, MinPageSize, size)
+
+	after, size, err = (&Pager{Token: keysetPageToken(16384), Size: 1000}).ParseKeyset()
+	require.NoError(t, err)
+	require.Equal(t, int64(16384), after)
+	require.Equal(t, MaxPageSize, size)
+
+	// Offsets from earlier versions and tokens of an unknown version are rejected.
+	for _, token := range []string{"500", "not a token", "azI6MTIz", "azE6LTE"} {
+		_, _, err = (&Pager{Token: token}).ParseKeyset()
+		require.ErrorIs(t, err, ErrInvalidPageToken, token)
+	}
+}
+
+func TestOIDKey(t *testing.T) {
+	require.Equal(t, int64(16384), oidKey(16384))
+	// An oid of 3000000000 is selected as "oid"::int as -1294967296.
+	require.Equal(t, int64(3000000000), oidKey(-1294967296))
+}
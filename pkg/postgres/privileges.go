@@ -86,6 +86,17 @@ func (ps PrivilegeSet) Name() string {
 	}
 }
 
+// PrivilegeSetFromName returns the privilege with the given name, as in GRANT statements and the privilege_type
+// column of aclexplode().
+func PrivilegeSetFromName(name string) PrivilegeSet {
+	for key := Insert; key < Terminator; key <<= 1 {
+		if strings.EqualFold(name, key.Name()) {
+			return key
+		}
+	}
+	return EmptyPrivilegeSet
+}
+
 func PrivilegeSetFromRune(s rune) PrivilegeSet {
 	for key := Insert; key < Terminator; key <<= 1 {
 		if string(s) == key.String() {
@@ -140,6 +151,11 @@ type ACL struct {
 	privsWithGrant PrivilegeSet
 	grantor        string
 	grantee        string
+
+	// resolved is set when the entry was decoded by the server, which also gives the grantee and grantor OIDs.
+	resolved  bool
+	granteeID int64
+	grantorID int64
 }
 
 func (a *ACL) Privileges() PrivilegeSet {
@@ -158,6 +174,29 @@ func (a *ACL) Grantee() string {
 	return a.grantee
 }
 
+func (a *ACL) Grantor() string {
+	return a.grantor
+}
+
+// GranteeID returns the OID of the grantee, 0 for PUBLIC. ok is false when the entry was parsed from its text form,
+// which only has names.
+func (a *ACL) GranteeID() (int64, bool) {
+	return a.granteeID, a.resolved
+}
+
+// GrantorID returns the OID of the grantor. ok is false when the entry was parsed from its text form.
+func (a *ACL) GrantorID() (int64, bool) {
+	return a.grantorID, a.resolved
+}
+
+// IsPublic reports whether the privileges are granted to PUBLIC.
+func (a *ACL) IsPublic() bool {
+	if a.resolved {
+		return a.granteeID == 0
+	}
+	return a.grantee == ""
+}
+
 func (a *ACL) String() string {
 	if a.privs == EmptyPrivilegeSet {
 		return ""
@@ -184,6 +223,8 @@ func (a *ACL) String() string {
 	return sb.String()
 }
 
+// NewACL parses the text form of an aclitem, e.g. foo=r*w/bar. It doesn't handle quoted role names, so prefer
+// DecodeACL on objects loaded with their ACL exploded by the server.
 func NewACL(acl string) (*ACL, error) {
 	ret := &ACL{}
 
@@ -1,8 +1,14 @@
 package postgres
 
 import (
+	"context"
 	"reflect"
 	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
 )
 
 func TestPrivilegeSet_Set(t *testing.T) {
@@ -191,6 +197,15 @@ func TestNewAcl(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"public is granted select by bar",
+			args{"=r/bar"},
+			&ACL{
+				grantor: "bar",
+				privs:   Select,
+			},
+			false,
+		},
 		{
 			"foo is granted select, insert with grant, create with grant, delete by bar",
 			args{"foo=ra*C*d/bar"},
@@ -355,3 +370,101 @@ func TestAcl_Check(t *testing.T) {
 		})
 	}
 }
+
+func TestPrivilegeSetFromName(t *testing.T) {
+	require.Equal(t, Select, PrivilegeSetFromName("SELECT"))
+	require.Equal(t, Temporary, PrivilegeSetFromName("TEMPORARY"))
+	require.Equal(t, AlterSystem, PrivilegeSetFromName("ALTER SYSTEM"))
+	require.Equal(t, EmptyPrivilegeSet, PrivilegeSetFromName("MAINTAIN"))
+}
+
+func TestACLExplodeUnsupported(t *testing.T) {
+	ctx := context.Background()
+	c := &Client{}
+
+	// Other undefined functions and objects don't mean aclexplode() is missing.
+	require.False(t, c.aclExplodeUnsupported(ctx, &pgconn.PgError{Code: "42704", Message: `type "aclitem" does not exist`}))
+	require.False(t, c.aclExplodeUnsupported(ctx, &pgconn.PgError{Code: "42883", Message: "function foo(integer) does not exist"}))
+	require.False(t, c.noACLExplode.Load())
+
+	require.True(t, c.aclExplodeUnsupported(ctx, &pgconn.PgError{Code: "42883", Message: "function aclexplode(aclitem[]) does not exist"}))
+	require.True(t, c.noACLExplode.Load())
+}
+
+func TestDecodeACL(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	_, err = client.db.Exec(ctx, `CREATE ROLE "we""ird=role/,x"`)
+	require.NoError(t, err)
+	_, err = client.db.Exec(ctx, `CREATE ROLE "acl_reader"`)
+	require.NoError(t, err)
+	_, err = client.db.Exec(ctx, `GRANT SELECT, INSERT ON "public"."test_table" TO "we""ird=role/,x" WITH GRANT OPTION`)
+	require.NoError(t, err)
+	_, err = client.db.Exec(ctx, `GRANT SELECT ON "public"."test_table" TO "acl_reader", PUBLIC`)
+	require.NoError(t, err)
+
+	var tableID int64
+	err = client.db.QueryRow(ctx, `SELECT 'public.test_table'::regclass::oid::int`).Scan(&tableID)
+	require.NoError(t, err)
+	table, err := client.GetTable(ctx, tableID)
+	require.NoError(t, err)
+	require.NotEmpty(t, table.ACLItems)
+
+	roleIDs, err := client.GetRoleIDsByName(ctx, []string{`we"ird=role/,x`, "acl_reader"})
+	require.NoError(t, err)
+
+	// The server decodes quoted names and gives the OIDs of grantees and grantors, in the query loading the table.
+	acls, err := DecodeACL(table)
+	require.NoError(t, err)
+
+	byGrantee := make(map[int64]*ACL)
+	for _, acl := range acls {
+		id, ok := acl.GranteeID()
+		require.True(t, ok)
+		byGrantee[id] = acl
+	}
+
+	weird := byGrantee[roleIDs[`we"ird=role/,x`]]
+	require.NotNil(t, weird)
+	require.Equal(t, `we"ird=role/,x`, weird.Grantee())
+	require.Equal(t, Select|Insert, weird.Privileges())
+	require.Equal(t, Select|Insert, weird.GrantPrivileges())
+
+	reader := byGrantee[roleIDs["acl_reader"]]
+	require.NotNil(t, reader)
+	require.Equal(t, Select, reader.Privileges())
+	require.Equal(t, EmptyPrivilegeSet, reader.GrantPrivileges())
+
+	public := byGrantee[0]
+	require.NotNil(t, public)
+	require.True(t, public.IsPublic())
+	require.Equal(t, Select, public.Privileges())
+
+	// Without aclexplode(), the text form is parsed instead and entries only have names.
+	client.noACLExplode.Store(true)
+	table, err = client.GetTable(ctx, tableID)
+	require.NoError(t, err)
+	require.Empty(t, table.ACLItems)
+	parsed, err := DecodeACL(table)
+	require.NoError(t, err)
+	require.Len(t, parsed, len(table.GetACLs()))
+
+	var sawPublic bool
+	for _, acl := range parsed {
+		_, ok := acl.GranteeID()
+		require.False(t, ok)
+		if acl.IsPublic() {
+			sawPublic = true
+			require.Equal(t, Select, acl.Privileges())
+		}
+		if acl.Grantee() == "acl_reader" {
+			require.Equal(t, Select, acl.Privileges())
+		}
+	}
+	require.True(t, sawPublic)
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v4"
@@ -22,6 +21,8 @@ type ProcedureModel struct {
 	OwnerID   int64    `db:"proowner"`
 	ACLs      []string `db:"proacl"`
 	Arguments string   `db:"arguments"`
+
+	explodedACL
 }
 
 func (t *ProcedureModel) GetOwnerID() int64 {
@@ -54,13 +55,14 @@ SELECT DISTINCT
        n."nspname",
        a."proowner"::int,
        a."proacl",
-       pg_get_function_arguments(a.oid) as arguments
+       pg_get_function_arguments(a.oid) as arguments,
+       %s
 FROM "pg_catalog"."pg_proc" a
          LEFT JOIN pg_namespace n ON n."oid" = a."pronamespace"
 WHERE a."oid" = $1
 `
 
-	err := pgxscan.Get(ctx, c.db, ret, q, functionID)
+	err := c.getWithACL(ctx, ret, q, `a."proacl"`, functionID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +74,7 @@ func (c *Client) ListProcedures(ctx context.Context, schemaID int64, pager *Page
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing procedures for schema", zap.Int64("schema_id", schemaID))
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -92,12 +94,10 @@ where a."prokind" = 'p'
   and a."pronamespace" = $1
 `)
 	args = append(args, schemaID)
-	_, _ = sb.WriteString("LIMIT $2 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $3")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`  AND a."oid" > $2::bigint::oid
+ORDER BY a."oid"
+LIMIT $3`)
+	args = append(args, after, limit+1)
 
 	var ret []*ProcedureModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -110,9 +110,8 @@ where a."prokind" = 'p'
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
@@ -138,6 +137,10 @@ func (c *Client) GrantProcedure(ctx context.Context, schema string, procedure *P
 }
 
 func (c *Client) RevokeProcedure(ctx context.Context, schema string, procedure *ProcedureModel, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 	l.Debug("revoking procedure", zap.String("principalName", principalName), zap.String("privilege", privilege))
 
@@ -157,3 +160,7 @@ func (c *Client) RevokeProcedure(ctx context.Context, schema string, procedure *
 	_, err := c.db.Exec(ctx, q)
 	return err
 }
+
+func (c *Client) SetProcedureOwner(ctx context.Context, schema string, procedure *ProcedureModel, ownerName string) error {
+	return c.alterOwner(ctx, "PROCEDURE", pgx.Identifier{schema}.Sanitize()+"."+procedure.Signature(), ownerName)
+}
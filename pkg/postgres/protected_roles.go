@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrProtectedRole is returned when a destructive operation targets a protected role.
+var ErrProtectedRole = errors.New("role is protected")
+
+// WithProtectedRoles protects roles from destructive operations, in addition to the role the client connects as
+// and the predefined pg_ roles, which are always protected.
+func WithProtectedRoles(roles []string) ClientOpt {
+	return func(c *Client) {
+		c.protectedRoles = make(map[string]bool, len(roles))
+		for _, r := range roles {
+			c.protectedRoles[r] = true
+		}
+	}
+}
+
+// IsProtectedRole reports whether roleName must not be deleted, have its password rotated or lose privileges.
+func (c *Client) IsProtectedRole(roleName string) bool {
+	if strings.HasPrefix(roleName, "pg_") {
+		return true
+	}
+
+	if roleName == c.cfg.ConnConfig.User {
+		return true
+	}
+
+	return c.protectedRoles[roleName]
+}
+
+// CheckProtectedRole returns an error wrapping ErrProtectedRole if roleName is protected.
+func (c *Client) CheckProtectedRole(roleName string) error {
+	if c.IsProtectedRole(roleName) {
+		return fmt.Errorf("refusing to modify role '%s': %w", roleName, ErrProtectedRole)
+	}
+
+	return nil
+}
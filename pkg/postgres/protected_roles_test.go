@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestProtectedRoles(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn(), WithProtectedRoles([]string{"test_role"}))
+	require.NoError(t, err)
+
+	// The role the client connects as, pg_ roles and configured roles are protected.
+	require.True(t, client.IsProtectedRole("postgres"))
+	require.True(t, client.IsProtectedRole("pg_read_all_data"))
+	require.True(t, client.IsProtectedRole("test_role"))
+	require.False(t, client.IsProtectedRole("test_user"))
+
+	err = client.DeleteRole(ctx, "test_role")
+	require.ErrorIs(t, err, ErrProtectedRole)
+
+	_, err = client.ChangePassword(ctx, "test_role", "new_password")
+	require.ErrorIs(t, err, ErrProtectedRole)
+
+	err = client.RevokeAllGrantsFromRole(ctx, "postgres")
+	require.ErrorIs(t, err, ErrProtectedRole)
+
+	err = client.RevokeTable(ctx, "public", "test_table", "test_role", "SELECT", false)
+	require.ErrorIs(t, err, ErrProtectedRole)
+
+	err = client.AlterRoleAttribute(ctx, "postgres", RoleAttributeSuperuser, false)
+	require.ErrorIs(t, err, ErrProtectedRole)
+
+	// Revoking test_role from an unprotected member is still allowed.
+	err = client.RevokeRole(ctx, "test_role", "test_user", false)
+	require.NoError(t, err)
+
+	_, err = client.GetRoleByName(ctx, "test_role")
+	require.NoError(t, err)
+}
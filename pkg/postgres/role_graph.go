@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// RoleGraph is an in-memory snapshot of every role and the memberships between them, so that grant computation
+// doesn't have to go back to the catalog for each object and each parent role.
+type RoleGraph struct {
+	roles             []*RoleModel
+	byID              map[int64]*RoleModel
+	byName            map[string]*RoleModel
+	hasMembers        map[int64]bool
+	membershipOptions bool
+
+	mtx       sync.Mutex
+	inherited map[int64][]*RoleModel
+}
+
+// LoadRoleGraph reads all roles and their memberships in a single query.
+func (c *Client) LoadRoleGraph(ctx context.Context) (*RoleGraph, error) {
+	l := ctxzap.Extract(ctx)
+
+	inheritFrom, err := c.inheritFromColumn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+SELECT r."oid"::int,
+       r."rolname",
+       r."rolsuper",
+       r."rolinherit",
+       r."rolcanlogin",
+       ARRAY(SELECT "roleid"::int
+             FROM "pg_catalog"."pg_auth_members"
+             WHERE "member" = r."oid") AS "member_of",
+       ` + inheritFrom + `
+FROM "pg_catalog"."pg_roles" r
+ORDER BY r."oid"
+`
+
+	var roles []*RoleModel
+	err = pgxscan.Select(ctx, c.db, &roles, q)
+	if err != nil {
+		return nil, err
+	}
+
+	membershipOptions, err := c.SupportsMembershipOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Debug("loaded role graph", zap.String("database", c.DatabaseName()), zap.Int("roles", len(roles)))
+
+	return NewRoleGraph(roles, membershipOptions), nil
+}
+
+// NewRoleGraph builds a graph from roles with their MemberOf and InheritFrom set. membershipOptions tells whether
+// the server sets INHERIT and SET per membership, as PostgreSQL 16 does.
+func NewRoleGraph(roles []*RoleModel, membershipOptions bool) *RoleGraph {
+	g := &RoleGraph{
+		roles:             make([]*RoleModel, len(roles)),
+		byID:              make(map[int64]*RoleModel, len(roles)),
+		byName:            make(map[string]*RoleModel, len(roles)),
+		hasMembers:        make(map[int64]bool),
+		membershipOptions: membershipOptions,
+		inherited:         make(map[int64][]*RoleModel),
+	}
+
+	copy(g.roles, roles)
+	sort.Slice(g.roles, func(i, j int) bool { return g.roles[i].ID < g.roles[j].ID })
+
+	for _, r := range g.roles {
+		g.byID[r.ID] = r
+		g.byName[r.Name] = r
+		for _, parentID := range r.MemberOf {
+			g.hasMembers[parentID] = true
+		}
+	}
+
+	return g
+}
+
+// Roles returns every role, ordered by ID.
+func (g *RoleGraph) Roles() []*RoleModel {
+	return g.roles
+}
+
+// Role returns the role with the given ID, or nil if there is none.
+func (g *RoleGraph) Role(id int64) *RoleModel {
+	return g.byID[id]
+}
+
+// RoleByName returns the role with the given name, or nil if there is none.
+func (g *RoleGraph) RoleByName(name string) *RoleModel {
+	return g.byName[name]
+}
+
+// MembershipOptions reports whether the server sets INHERIT and SET per membership.
+func (g *RoleGraph) MembershipOptions() bool {
+	return g.membershipOptions
+}
+
+// HasMembers reports whether any role is a member of the role with the given ID.
+func (g *RoleGraph) HasMembers(id int64) bool {
+	return g.hasMembers[id]
+}
+
+// InheritedRoles returns every role whose privileges the role with the given ID inherits, directly or through
+// other roles. Membership cycles are only followed once.
+func (g *RoleGraph) InheritedRoles(id int64) []*RoleModel {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if ret, ok := g.inherited[id]; ok {
+		return ret
+	}
+
+	var ret []*RoleModel
+	seen := map[int64]bool{id: true}
+	queue := []int64{id}
+	for len(queue) > 0 {
+		r := g.byID[queue[0]]
+		queue = queue[1:]
+		if r == nil {
+			continue
+		}
+
+		for _, parentID := range r.InheritFrom {
+			if seen[parentID] {
+				continue
+			}
+			seen[parentID] = true
+
+			if parent := g.byID[parentID]; parent != nil {
+				ret = append(ret, parent)
+				queue = append(queue, parentID)
+			}
+		}
+	}
+
+	g.inherited[id] = ret
+	return ret
+}
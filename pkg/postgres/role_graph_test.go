@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestRoleGraphInheritance(t *testing.T) {
+	// 4 inherits from 3, which inherits from 2 and 1. 2 and 3 inherit from each other. 5 is a member of 1 without
+	// inheriting from it.
+	g := NewRoleGraph([]*RoleModel{
+		{ID: 5, Name: "noinherit", MemberOf: []int64{1}},
+		{ID: 4, Name: "user", MemberOf: []int64{3}, InheritFrom: []int64{3}},
+		{ID: 3, Name: "team", MemberOf: []int64{1, 2}, InheritFrom: []int64{1, 2}},
+		{ID: 2, Name: "cycle", MemberOf: []int64{3}, InheritFrom: []int64{3}},
+		{ID: 1, Name: "readers"},
+	}, true)
+
+	names := func(roles []*RoleModel) []string {
+		var ret []string
+		for _, r := range roles {
+			ret = append(ret, r.Name)
+		}
+		return ret
+	}
+
+	require.ElementsMatch(t, []string{"team", "readers", "cycle"}, names(g.InheritedRoles(4)))
+	require.ElementsMatch(t, []string{"team", "readers"}, names(g.InheritedRoles(2)))
+	require.Empty(t, g.InheritedRoles(5))
+	require.Empty(t, g.InheritedRoles(42))
+
+	require.True(t, g.HasMembers(1))
+	require.False(t, g.HasMembers(4))
+	require.Equal(t, "team", g.Role(3).Name)
+	require.Equal(t, int64(3), g.RoleByName("team").ID)
+	require.Nil(t, g.RoleByName("nobody"))
+	require.True(t, g.MembershipOptions())
+	require.Nil(t, g.Role(42))
+	require.Equal(t, []string{"readers", "cycle", "team", "user", "noinherit"}, names(g.Roles()))
+}
+
+func TestLoadRoleGraph(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	g, err := client.LoadRoleGraph(ctx)
+	require.NoError(t, err)
+
+	user, err := client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	role, err := client.GetRoleByName(ctx, "test_role")
+	require.NoError(t, err)
+
+	require.NotNil(t, g.Role(user.ID))
+	require.True(t, g.HasMembers(role.ID))
+	require.Len(t, g.InheritedRoles(user.ID), 1)
+	require.Equal(t, "test_role", g.InheritedRoles(user.ID)[0].Name)
+}
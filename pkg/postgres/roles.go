@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
@@ -17,19 +18,64 @@ import (
 var errRevokeGrantsFromRole = errors.New("error revoking grants from role")
 var errRevokeParentRolesFromRole = errors.New("error revoking parent roles from role")
 
+// Role attributes that can be toggled with ALTER ROLE.
+const (
+	RoleAttributeSuperuser   = "SUPERUSER"
+	RoleAttributeCreateDb    = "CREATEDB"
+	RoleAttributeCreateRole  = "CREATEROLE"
+	RoleAttributeBypassRLS   = "BYPASSRLS"
+	RoleAttributeReplication = "REPLICATION"
+)
+
+var roleAttributes = map[string]bool{
+	RoleAttributeSuperuser:   true,
+	RoleAttributeCreateDb:    true,
+	RoleAttributeCreateRole:  true,
+	RoleAttributeBypassRLS:   true,
+	RoleAttributeReplication: true,
+}
+
+// membershipOptionsVersionNum is the first server version (PostgreSQL 16) that tracks INHERIT and SET
+// per role membership instead of deriving them from the member's rolinherit.
+const membershipOptionsVersionNum = 160000
+
+// Per-membership options that can be granted and revoked on PostgreSQL 16+.
+const (
+	RoleOptionInherit = "INHERIT"
+	RoleOptionSet     = "SET"
+)
+
+var roleOptions = map[string]bool{
+	RoleOptionInherit: true,
+	RoleOptionSet:     true,
+}
+
 type RoleModel struct {
-	ID                int64   `db:"oid"`
-	Name              string  `db:"rolname"`
-	Superuser         bool    `db:"rolsuper"`
-	Inherit           bool    `db:"rolinherit"`
-	CreateRole        bool    `db:"rolcreaterole"`
-	CreateDb          bool    `db:"rolcreatedb"`
-	CanLogin          bool    `db:"rolcanlogin"`
-	Replication       bool    `db:"rolreplication"`
-	ConnectionLimit   int     `db:"rolconnlimit"`
-	BypassRowSecurity bool    `db:"rolbypassrls"`
-	RoleAdmin         *bool   `db:"admin_option"`
-	MemberOf          []int64 `db:"member_of"`
+	ID                int64               `db:"oid"`
+	Name              string              `db:"rolname"`
+	Superuser         bool                `db:"rolsuper"`
+	Inherit           bool                `db:"rolinherit"`
+	CreateRole        bool                `db:"rolcreaterole"`
+	CreateDb          bool                `db:"rolcreatedb"`
+	CanLogin          bool                `db:"rolcanlogin"`
+	Replication       bool                `db:"rolreplication"`
+	ConnectionLimit   int                 `db:"rolconnlimit"`
+	BypassRowSecurity bool                `db:"rolbypassrls"`
+	ValidUntil        *time.Time          `db:"rolvaliduntil"`
+	Comment           *string             `db:"description"`
+	Settings          map[string][]string `db:"role_settings"`
+	SecurityLabels    map[string]string   `db:"security_labels"`
+	RoleAdmin         *bool               `db:"admin_option"`
+	InheritOption     *bool               `db:"inherit_option"`
+	SetOption         *bool               `db:"set_option"`
+	MemberOf          []int64             `db:"member_of"`
+	InheritFrom       []int64             `db:"inherit_from"`
+}
+
+// IsExpired reports whether the role's VALID UNTIL has passed, after which it can no longer log in with a password.
+// ValidUntil is nil when the role never expires.
+func (r *RoleModel) IsExpired(now time.Time) bool {
+	return r.ValidUntil != nil && r.ValidUntil.Before(now)
 }
 
 func (r *RoleModel) IsRoleAdmin() bool {
@@ -40,21 +86,37 @@ func (r *RoleModel) IsRoleAdmin() bool {
 	return *r.RoleAdmin
 }
 
-func (c *Client) RoleHasMembers(ctx context.Context, roleID int64) (bool, error) {
-	query := `SELECT EXISTS(SELECT "roleid" FROM "pg_catalog"."pg_auth_members" WHERE "roleid" = $1)`
+// InheritsRole reports whether a role member inherits the privileges of the role it was listed for.
+func (r *RoleModel) InheritsRole() bool {
+	if r.InheritOption == nil {
+		return false
+	}
 
-	var ret bool
-	err := c.db.QueryRow(ctx, query, roleID).Scan(&ret)
+	return *r.InheritOption
+}
+
+// CanSetRole reports whether a role member can SET ROLE to the role it was listed for.
+func (r *RoleModel) CanSetRole() bool {
+	if r.SetOption == nil {
+		return false
+	}
+
+	return *r.SetOption
+}
+
+// SupportsMembershipOptions reports whether the server tracks INHERIT and SET per role membership.
+func (c *Client) SupportsMembershipOptions(ctx context.Context) (bool, error) {
+	versionNum, err := c.ServerVersionNum(ctx)
 	if err != nil {
 		return false, err
 	}
 
-	return ret, nil
+	return versionNum >= membershipOptionsVersionNum, nil
 }
 
-func (c *Client) GetRoleByName(ctx context.Context, roleName string) (*RoleModel, error) {
-	q := `
-SELECT r."rolname",
+// roleColumns selects the attributes, comment, settings, security labels and oid of the role r, in the
+// columns scanned into RoleModel.
+const roleColumns = `r."rolname",
        r."rolsuper",
        r."rolinherit",
        r."rolcreaterole",
@@ -63,20 +125,76 @@ SELECT r."rolname",
        r."rolreplication",
        r."rolconnlimit",
        r."rolbypassrls",
-       r."oid"::int,
+       CASE WHEN isfinite(r."rolvaliduntil") THEN r."rolvaliduntil" END AS "rolvaliduntil",
+       shobj_description(r."oid", 'pg_authid') AS "description",
+       COALESCE((SELECT jsonb_object_agg(COALESCE(d."datname", '*'), s."setconfig")
+                 FROM "pg_catalog"."pg_db_role_setting" s
+                          LEFT JOIN "pg_catalog"."pg_database" d ON d."oid" = s."setdatabase"
+                 WHERE s."setrole" = r."oid"), '{}') AS "role_settings",
+       COALESCE((SELECT jsonb_object_agg(l."provider", l."label")
+                 FROM "pg_catalog"."pg_shseclabel" l
+                 WHERE l."classoid" = 'pg_catalog.pg_authid'::regclass
+                   AND l."objoid" = r."oid"), '{}') AS "security_labels",
+       r."oid"::int`
+
+// memberOfColumn selects the roles r is a direct member of.
+const memberOfColumn = `ARRAY(SELECT "roleid"::int
+             FROM "pg_catalog"."pg_auth_members"
+             WHERE "member" = r."oid") AS "member_of"`
+
+// inheritFromColumn returns the select expression for the roles whose privileges r inherits. Before
+// PostgreSQL 16 this is every role r is a member of, as long as r has the INHERIT attribute.
+func (c *Client) inheritFromColumn(ctx context.Context) (string, error) {
+	supported, err := c.SupportsMembershipOptions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if supported {
+		return `ARRAY(SELECT "roleid"::int
+             FROM "pg_catalog"."pg_auth_members"
+             WHERE "member" = r."oid"
+               AND "inherit_option") AS "inherit_from"`, nil
+	}
+
+	return `CASE
+           WHEN r."rolinherit" THEN ARRAY(SELECT "roleid"::int
+                                          FROM "pg_catalog"."pg_auth_members"
+                                          WHERE "member" = r."oid")
+           ELSE '{}'::int[]
+           END AS "inherit_from"`, nil
+}
+
+func (c *Client) RoleHasMembers(ctx context.Context, roleID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT "roleid" FROM "pg_catalog"."pg_auth_members" WHERE "roleid" = $1)`
+
+	var ret bool
+	err := c.db.QueryRow(ctx, query, roleID).Scan(&ret)
+	if err != nil {
+		return false, err
+	}
+
+	return ret, nil
+}
+
+func (c *Client) GetRoleByName(ctx context.Context, roleName string) (*RoleModel, error) {
+	inheritFrom, err := c.inheritFromColumn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+SELECT ` + roleColumns + `,
        m."admin_option",
-       ARRAY
-           (SELECT "roleid"::int
-            FROM "pg_catalog"."pg_auth_members"
-            where member = r."oid")
-           AS "member_of"
+       ` + memberOfColumn + `,
+       ` + inheritFrom + `
 FROM "pg_catalog"."pg_roles" r
          LEFT JOIN "pg_auth_members" m ON m."member" = r."oid"
 WHERE r."rolname" = $1
 `
 
 	role := &RoleModel{}
-	err := pgxscan.Get(ctx, c.db, role, q, roleName)
+	err = pgxscan.Get(ctx, c.db, role, q, roleName)
 	if err != nil {
 		return nil, err
 	}
@@ -85,29 +203,23 @@ WHERE r."rolname" = $1
 }
 
 func (c *Client) GetRole(ctx context.Context, roleID int64) (*RoleModel, error) {
+	inheritFrom, err := c.inheritFromColumn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	q := `
-SELECT DISTINCT
-       r."rolname",
-       r."rolsuper",
-       r."rolinherit",
-       r."rolcreaterole",
-       r."rolcreatedb",
-       r."rolcanlogin",
-       r."rolreplication",
-       r."rolconnlimit",
-       r."rolbypassrls",
-       r."oid"::int,
+SELECT DISTINCT ` + roleColumns + `,
        m."admin_option",
-       ARRAY(SELECT "roleid"::int
-             FROM "pg_catalog"."pg_auth_members"
-             where member = r."oid") AS "member_of"
+       ` + memberOfColumn + `,
+       ` + inheritFrom + `
 FROM "pg_catalog"."pg_roles" r
          LEFT JOIN "pg_auth_members" m ON m."member" = r."oid"
 WHERE r."oid" = $1
 `
 
 	role := &RoleModel{}
-	err := pgxscan.Get(ctx, c.db, role, q, roleID)
+	err = pgxscan.Get(ctx, c.db, role, q, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -115,20 +227,53 @@ WHERE r."oid" = $1
 	return role, nil
 }
 
-func (c *Client) GrantRole(ctx context.Context, roleName string, principalName string) error {
+// GrantRole grants membership in roleName to principalName. With adminOption the member can also
+// grant the role to others.
+func (c *Client) GrantRole(ctx context.Context, roleName string, principalName string, adminOption bool) error {
 	l := ctxzap.Extract(ctx)
 
 	sanitizedRoleName := pgx.Identifier{roleName}.Sanitize()
 	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
 
 	query := "GRANT " + sanitizedRoleName + " TO " + sanitizedPrincipalName
+	if adminOption {
+		query += " WITH ADMIN OPTION"
+	}
 	l.Debug("granting role to member", zap.String("query", query))
 
 	_, err := c.db.Exec(ctx, query)
 	return err
 }
 
-func (c *Client) RevokeRole(ctx context.Context, roleName string, target string, isGrant bool) error {
+// AlterRoleAttribute sets (or with enabled false, clears) a role attribute such as SUPERUSER.
+func (c *Client) AlterRoleAttribute(ctx context.Context, roleName string, attribute string, enabled bool) error {
+	l := ctxzap.Extract(ctx)
+
+	if !roleAttributes[attribute] {
+		return fmt.Errorf("unsupported role attribute: %s", attribute)
+	}
+
+	if !enabled {
+		if err := c.CheckProtectedRole(roleName); err != nil {
+			return err
+		}
+		attribute = "NO" + attribute
+	}
+
+	query := fmt.Sprintf("ALTER ROLE %s WITH %s", pgx.Identifier{roleName}.Sanitize(), attribute)
+	l.Debug("altering role attribute", zap.String("query", query))
+
+	_, err := c.db.Exec(ctx, query)
+	return err
+}
+
+// RevokeRole revokes membership in roleName from target. With adminOption only the admin option is
+// revoked and the membership itself is kept.
+func (c *Client) RevokeRole(ctx context.Context, roleName string, target string, adminOption bool) error {
+	if err := c.CheckProtectedRole(target); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 
 	sanitizedRoleName := pgx.Identifier{roleName}.Sanitize()
@@ -136,8 +281,8 @@ func (c *Client) RevokeRole(ctx context.Context, roleName string, target string,
 
 	query := "REVOKE " + sanitizedRoleName + " FROM " + sanitizedTarget
 
-	if isGrant {
-		query = "REVOKE GRANT OPTION FOR " + sanitizedRoleName + " FROM " + sanitizedTarget
+	if adminOption {
+		query = "REVOKE ADMIN OPTION FOR " + sanitizedRoleName + " FROM " + sanitizedTarget
 	}
 
 	l.Debug("revoking role from member", zap.String("query", query))
@@ -145,6 +290,215 @@ func (c *Client) RevokeRole(ctx context.Context, roleName string, target string,
 	return err
 }
 
+// GrantRoleWithOptions grants membership in roleName to principalName with explicit INHERIT and SET
+// options. If principalName already is a member the options of the existing membership are updated.
+// Requires PostgreSQL 16+.
+func (c *Client) GrantRoleWithOptions(ctx context.Context, roleName string, principalName string, inherit bool, set bool) error {
+	l := ctxzap.Extract(ctx)
+
+	query := fmt.Sprintf(
+		"GRANT %s TO %s WITH INHERIT %s, SET %s",
+		pgx.Identifier{roleName}.Sanitize(),
+		pgx.Identifier{principalName}.Sanitize(),
+		strings.ToUpper(strconv.FormatBool(inherit)),
+		strings.ToUpper(strconv.FormatBool(set)),
+	)
+	l.Debug("granting role to member with options", zap.String("query", query))
+
+	_, err := c.db.Exec(ctx, query)
+	return err
+}
+
+// RevokeRoleOption revokes the INHERIT or SET option of the membership of target in roleName, keeping the
+// membership itself. Requires PostgreSQL 16+.
+func (c *Client) RevokeRoleOption(ctx context.Context, roleName string, target string, option string) error {
+	if err := c.CheckProtectedRole(target); err != nil {
+		return err
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	if !roleOptions[option] {
+		return fmt.Errorf("unsupported role membership option: %s", option)
+	}
+
+	query := fmt.Sprintf(
+		"REVOKE %s OPTION FOR %s FROM %s",
+		option,
+		pgx.Identifier{roleName}.Sanitize(),
+		pgx.Identifier{target}.Sanitize(),
+	)
+	l.Debug("revoking role membership option", zap.String("query", query))
+
+	_, err := c.db.Exec(ctx, query)
+	return err
+}
+
+// DisableRole stops roleName from logging in by setting NOLOGIN, and with expire also sets VALID UNTIL 'now'. What it
+// changes is recorded in baton.disabled_roles first, so that EnableRole can restore it; disabling a role again keeps
+// the state it had before it was first disabled. Sessions the role already has open are terminated. It returns the
+// number of terminated sessions.
+func (c *Client) DisableRole(ctx context.Context, roleName string, expire bool) (int, error) {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return 0, err
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	role, err := c.GetRoleByName(ctx, roleName)
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.ensureDisabledRolesTable(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// A role that is already disabled keeps its recorded state, except for the VALID UNTIL of a role that only now
+	// gets expired.
+	_, err = tx.Exec(ctx, `
+INSERT INTO "baton"."disabled_roles" AS d ("role_id", "role_name", "could_login", "expired", "valid_until")
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT ("role_id") DO UPDATE
+    SET "role_name"   = EXCLUDED."role_name",
+        "valid_until" = CASE WHEN d."expired" THEN d."valid_until" ELSE EXCLUDED."valid_until" END,
+        "expired"     = d."expired" OR EXCLUDED."expired"
+`,
+		role.ID,
+		role.Name,
+		role.CanLogin,
+		expire,
+		role.ValidUntil,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "ALTER ROLE " + pgx.Identifier{roleName}.Sanitize() + " WITH NOLOGIN"
+	if expire {
+		query += " VALID UNTIL 'now'"
+	}
+	l.Debug("disabling role", zap.String("query", query))
+
+	_, err = tx.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	// The sessions are selected first and terminated in the target list. As a WHERE condition, the planner could
+	// push pg_terminate_backend() below the join that resolves usename and terminate every backend.
+	terminateQuery := `
+SELECT count(*) FILTER (WHERE pg_terminate_backend(s."pid"))
+FROM (SELECT "pid"
+      FROM "pg_catalog"."pg_stat_activity"
+      WHERE "usename" = $1
+        AND "pid" <> pg_backend_pid()) s
+`
+	var terminated int
+	err = c.db.QueryRow(ctx, terminateQuery, roleName).Scan(&terminated)
+	if err != nil {
+		return 0, err
+	}
+	l.Debug("terminated role sessions", zap.String("role", roleName), zap.Int("sessions", terminated))
+
+	return terminated, nil
+}
+
+// EnableRole reverses DisableRole: it sets LOGIN if the role could log in before it was disabled, and restores the
+// VALID UNTIL it had if disabling it expired it. Roles that weren't disabled with DisableRole are refused.
+func (c *Client) EnableRole(ctx context.Context, roleName string) error {
+	l := ctxzap.Extract(ctx)
+
+	role, err := c.GetRoleByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	exists, err := c.disabledRolesTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("role %s was not disabled by the connector", roleName)
+	}
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	q := `
+SELECT "role_id",
+       "role_name",
+       "could_login",
+       "expired",
+       "valid_until",
+       "disabled_at"
+FROM "baton"."disabled_roles"
+WHERE "role_id" = $1
+    FOR UPDATE
+`
+
+	disabled := &DisabledRoleModel{}
+	err = pgxscan.Get(ctx, tx, disabled, q, role.ID)
+	if err != nil {
+		if pgxscan.NotFound(err) {
+			return fmt.Errorf("role %s was not disabled by the connector", roleName)
+		}
+		return err
+	}
+
+	var options []string
+	var args []interface{}
+	if disabled.CouldLogin {
+		options = append(options, "LOGIN")
+	}
+	if disabled.Expired {
+		// A NULL VALID UNTIL can't be set again, 'infinity' is how a role is made to never expire.
+		validUntil := "infinity"
+		if disabled.ValidUntil != nil {
+			validUntil = disabled.ValidUntil.UTC().Format(time.RFC3339Nano)
+		}
+		args = append(args, validUntil)
+		options = append(options, fmt.Sprintf("VALID UNTIL $%d", len(args)))
+	}
+
+	if len(options) > 0 {
+		query := "ALTER ROLE " + pgx.Identifier{roleName}.Sanitize() + " WITH " + strings.Join(options, " ")
+		l.Debug("enabling role", zap.String("query", query))
+
+		// Utility statements can't take bind parameters, so the expiry is interpolated client side.
+		_, err = tx.Exec(ctx, query, append([]interface{}{pgx.QuerySimpleProtocol(true)}, args...)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM "baton"."disabled_roles" WHERE "role_id" = $1`, role.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (c *Client) CreateRole(ctx context.Context, roleName string) error {
 	l := ctxzap.Extract(ctx)
 
@@ -202,6 +556,16 @@ func (c *Client) RoleOwnsObjects(ctx context.Context, roleName string) (bool, er
 
 // RevokeAllGrantsFromRole revokes all grants from a role across all schemas.
 func (c *Client) RevokeAllGrantsFromRole(ctx context.Context, roleName string) error {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return err
+	}
+
+	_, err := c.revokeAllGrantsFromRole(ctx, roleName)
+	return err
+}
+
+// revokeAllGrantsFromRole does the work of RevokeAllGrantsFromRole and also returns the schemas it went through.
+func (c *Client) revokeAllGrantsFromRole(ctx context.Context, roleName string) ([]string, error) {
 	l := ctxzap.Extract(ctx)
 
 	sanitizedRoleName := pgx.Identifier{roleName}.Sanitize()
@@ -216,7 +580,7 @@ func (c *Client) RevokeAllGrantsFromRole(ctx context.Context, roleName string) e
 	rows, err := c.db.Query(ctx, schemasQuery)
 	if err != nil {
 		l.Error("error querying schemas", zap.Error(err))
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -225,14 +589,14 @@ func (c *Client) RevokeAllGrantsFromRole(ctx context.Context, roleName string) e
 		var schemaName string
 		if err := rows.Scan(&schemaName); err != nil {
 			l.Error("error scanning schema name", zap.Error(err))
-			return err
+			return nil, err
 		}
 		schemas = append(schemas, schemaName)
 	}
 
 	if err := rows.Err(); err != nil {
 		l.Error("error iterating schemas", zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	var revokeError error
@@ -311,14 +675,147 @@ func (c *Client) RevokeAllGrantsFromRole(ctx context.Context, roleName string) e
 	}
 
 	if revokeError != nil {
-		return errors.Join(errRevokeGrantsFromRole, revokeError)
+		return schemas, errors.Join(errRevokeGrantsFromRole, revokeError)
 	}
 
-	return nil
+	return schemas, nil
+}
+
+// defaultACLObjectTypes maps pg_default_acl.defaclobjtype to the object kind used by ALTER DEFAULT PRIVILEGES.
+var defaultACLObjectTypes = map[string]string{
+	"r": "TABLES",
+	"S": "SEQUENCES",
+	"f": "FUNCTIONS",
+	"T": "TYPES",
+	"n": "SCHEMAS",
+}
+
+// RevokeDefaultPrivilegesFromRole removes roleName from the default privileges (pg_default_acl) of the client's
+// database, both where it is a grantee and where it defined default privileges for others. It returns the number
+// of default privilege entries that were revoked.
+func (c *Client) RevokeDefaultPrivilegesFromRole(ctx context.Context, roleName string) (int, error) {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return 0, err
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	// The global default privileges a role defines for itself are the hard-wired defaults, so they are left alone.
+	query := `
+SELECT DISTINCT pg_get_userbyid(d."defaclrole") AS "owner",
+                n."nspname",
+                d."defaclobjtype"::text,
+                a."grantee" = 0 AS "is_public",
+                pg_get_userbyid(a."grantee") AS "grantee"
+FROM "pg_catalog"."pg_default_acl" d
+         LEFT JOIN "pg_catalog"."pg_namespace" n ON n."oid" = d."defaclnamespace"
+         CROSS JOIN LATERAL aclexplode(d."defaclacl") a
+         JOIN "pg_catalog"."pg_roles" r ON r."rolname" = $1
+WHERE (a."grantee" = r."oid" OR d."defaclrole" = r."oid")
+  AND NOT (d."defaclnamespace" = 0 AND a."grantee" = d."defaclrole")
+`
+
+	rows, err := c.db.Query(ctx, query, roleName)
+	if err != nil {
+		return 0, err
+	}
+
+	var statements []string
+	for rows.Next() {
+		var owner, objType, grantee string
+		var schema *string
+		var isPublic bool
+		if err := rows.Scan(&owner, &schema, &objType, &isPublic, &grantee); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		objKind, ok := defaultACLObjectTypes[objType]
+		if !ok {
+			l.Warn("skipping default privileges with unknown object type", zap.String("object_type", objType))
+			continue
+		}
+
+		sanitizedGrantee := "PUBLIC"
+		if !isPublic {
+			sanitizedGrantee = pgx.Identifier{grantee}.Sanitize()
+		}
+
+		q := "ALTER DEFAULT PRIVILEGES FOR ROLE " + pgx.Identifier{owner}.Sanitize()
+		if schema != nil {
+			q += " IN SCHEMA " + pgx.Identifier{*schema}.Sanitize()
+		}
+		q += fmt.Sprintf(" REVOKE ALL ON %s FROM %s", objKind, sanitizedGrantee)
+		statements = append(statements, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var revokeError error
+	revoked := 0
+	for _, q := range statements {
+		l.Debug("revoking default privileges", zap.String("query", q))
+		if _, err := c.db.Exec(ctx, q); err != nil {
+			l.Warn("error revoking default privileges", zap.String("query", q), zap.Error(err))
+			revokeError = errors.Join(revokeError, err)
+			continue
+		}
+		revoked++
+	}
+
+	if revokeError != nil {
+		return revoked, errors.Join(errRevokeGrantsFromRole, revokeError)
+	}
+
+	return revoked, nil
+}
+
+// RoleGrantsCleanup describes what CleanupRoleGrants revoked from a role in a single database.
+type RoleGrantsCleanup struct {
+	Schemas           []string
+	DefaultPrivileges int
+	// Err holds the revokes that failed without stopping the cleanup.
+	Err error
+}
+
+// CleanupRoleGrants revokes every privilege roleName holds in the client's database, including its default
+// privileges, so that nothing in this database keeps the role from being dropped.
+func (c *Client) CleanupRoleGrants(ctx context.Context, roleName string) (*RoleGrantsCleanup, error) {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return nil, err
+	}
+
+	ret := &RoleGrantsCleanup{}
+
+	schemas, err := c.revokeAllGrantsFromRole(ctx, roleName)
+	if err != nil {
+		if !errors.Is(err, errRevokeGrantsFromRole) {
+			return nil, err
+		}
+		ret.Err = errors.Join(ret.Err, err)
+	}
+	ret.Schemas = schemas
+
+	revoked, err := c.RevokeDefaultPrivilegesFromRole(ctx, roleName)
+	if err != nil {
+		if !errors.Is(err, errRevokeGrantsFromRole) {
+			return nil, err
+		}
+		ret.Err = errors.Join(ret.Err, err)
+	}
+	ret.DefaultPrivileges = revoked
+
+	return ret, nil
 }
 
 // RemoveRoleFromAllRoles removes a role from all other roles.
 func (c *Client) RemoveRoleFromAllRoles(ctx context.Context, roleName string) error {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 
 	sanitizedRoleName := pgx.Identifier{roleName}.Sanitize()
@@ -373,8 +870,84 @@ func (c *Client) RemoveRoleFromAllRoles(ctx context.Context, roleName string) er
 	return nil
 }
 
-// SafeDeleteRole safely deletes a role by first revoking grants and removing memberships.
+// ReassignOwned transfers ownership of everything roleName owns in the client's database to successor and then
+// drops the privileges roleName still holds there, so that the role can be dropped. Shared objects such as
+// databases are reassigned by whichever database runs this first, so they are only counted when includeShared is
+// set. It returns the number of objects that were reassigned, keyed by the catalog they live in (e.g. pg_class).
+func (c *Client) ReassignOwned(ctx context.Context, roleName string, successor string, includeShared bool) (map[string]int64, error) {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return nil, err
+	}
+
+	l := ctxzap.Extract(ctx)
+
+	countQuery := `
+SELECT d."classid"::regclass::text AS "catalog",
+       count(*)                    AS "count"
+FROM "pg_catalog"."pg_shdepend" d
+WHERE d."refclassid" = 'pg_catalog.pg_authid'::regclass
+  AND d."refobjid" = (SELECT "oid" FROM "pg_catalog"."pg_roles" WHERE "rolname" = $1)
+  AND d."deptype" = 'o'
+  AND (d."dbid" = (SELECT "oid" FROM "pg_catalog"."pg_database" WHERE "datname" = current_database())
+    OR ($2 AND d."dbid" = 0))
+GROUP BY d."classid"
+`
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	rows, err := tx.Query(ctx, countQuery, roleName, includeShared)
+	if err != nil {
+		return nil, err
+	}
+	reassigned := make(map[string]int64)
+	for rows.Next() {
+		var catalog string
+		var count int64
+		if err := rows.Scan(&catalog, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		reassigned[catalog] = count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sanitizedRoleName := pgx.Identifier{roleName}.Sanitize()
+
+	reassignQuery := fmt.Sprintf("REASSIGN OWNED BY %s TO %s", sanitizedRoleName, pgx.Identifier{successor}.Sanitize())
+	l.Debug("reassigning owned objects", zap.String("query", reassignQuery))
+	if _, err := tx.Exec(ctx, reassignQuery); err != nil {
+		return nil, err
+	}
+
+	dropQuery := "DROP OWNED BY " + sanitizedRoleName
+	l.Debug("dropping owned privileges", zap.String("query", dropQuery))
+	if _, err := tx.Exec(ctx, dropQuery); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return reassigned, nil
+}
+
+// SafeDeleteRole safely deletes a role by first removing its memberships. It refuses roles that still own objects in
+// the client's database; the grants the role holds must already have been revoked, see CleanupRoleGrants.
 func (c *Client) SafeDeleteRole(ctx context.Context, roleName string) error {
+	if err := c.CheckProtectedRole(roleName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 
 	if roleName == "" {
@@ -391,15 +964,6 @@ func (c *Client) SafeDeleteRole(ctx context.Context, roleName string) error {
 		return fmt.Errorf("cannot delete role '%s': role owns database objects (tables, schemas, functions, etc.). Please transfer ownership or drop objects first", roleName)
 	}
 
-	l.Debug("revoking all grants from role", zap.String("role", roleName))
-	grantsRevokeError := c.RevokeAllGrantsFromRole(ctx, roleName)
-	if grantsRevokeError != nil {
-		l.Error("error revoking grants from role", zap.Error(grantsRevokeError))
-		if !errors.Is(grantsRevokeError, errRevokeGrantsFromRole) {
-			return fmt.Errorf("error revoking existing grants from role: %w", grantsRevokeError)
-		}
-	}
-
 	l.Debug("removing role from all parent roles", zap.String("role", roleName))
 	roleRevokeError := c.RemoveRoleFromAllRoles(ctx, roleName)
 	if roleRevokeError != nil {
@@ -415,7 +979,7 @@ func (c *Client) SafeDeleteRole(ctx context.Context, roleName string) error {
 	_, err = c.db.Exec(ctx, query)
 	if err != nil {
 		l.Error("error dropping role", zap.Error(err))
-		finalError := errors.Join(err, roleRevokeError, grantsRevokeError)
+		finalError := errors.Join(err, roleRevokeError)
 		return fmt.Errorf("error dropping role(%s): %w", roleName, finalError)
 	}
 
@@ -428,28 +992,103 @@ func (c *Client) DeleteRole(ctx context.Context, roleName string) error {
 }
 
 func (c *Client) CreateUser(ctx context.Context, login string, password string) (*RoleModel, error) {
+	if password == "" {
+		return nil, errors.New("password cannot be empty")
+	}
+
+	return c.CreateUserWithOptions(ctx, login, &CreateUserOptions{Password: password})
+}
+
+// CreateUserOptions are the attributes a login role is created with. An empty Password creates a role without
+// one, for roles that authenticate through pg_hba.conf methods such as cert, ldap or gss. Groups are the roles
+// the new role is made a member of, and Comment is set as the role's COMMENT.
+type CreateUserOptions struct {
+	Password        string
+	Groups          []string
+	ConnectionLimit *int
+	ValidUntil      *time.Time
+	Comment         string
+}
+
+// CreateUserWithOptions creates a login role and adds it to its groups in a single transaction, so a role is
+// never left behind without the memberships it was requested with.
+func (c *Client) CreateUserWithOptions(ctx context.Context, login string, opts *CreateUserOptions) (*RoleModel, error) {
 	l := ctxzap.Extract(ctx)
 
 	if login == "" {
 		return nil, errors.New("login cannot be empty")
 	}
-	if password == "" {
-		return nil, errors.New("password cannot be empty")
+	if opts == nil {
+		opts = &CreateUserOptions{}
 	}
 
 	sanitizedLogin := pgx.Identifier{login}.Sanitize()
-	query := fmt.Sprintf("CREATE ROLE %s WITH LOGIN PASSWORD $1", sanitizedLogin)
+
+	var args []interface{}
+	query := fmt.Sprintf("CREATE ROLE %s WITH LOGIN", sanitizedLogin)
+	if opts.Password != "" {
+		args = append(args, opts.Password)
+		query += fmt.Sprintf(" PASSWORD $%d", len(args))
+	}
+	if opts.ConnectionLimit != nil {
+		if *opts.ConnectionLimit < -1 {
+			return nil, fmt.Errorf("invalid connection limit %d", *opts.ConnectionLimit)
+		}
+		query += fmt.Sprintf(" CONNECTION LIMIT %d", *opts.ConnectionLimit)
+	}
+	if opts.ValidUntil != nil {
+		args = append(args, opts.ValidUntil.UTC().Format(time.RFC3339))
+		query += fmt.Sprintf(" VALID UNTIL $%d", len(args))
+	}
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
 
 	l.Debug("creating user", zap.String("query", query))
 
-	_, err := c.db.Exec(ctx, query, pgx.QuerySimpleProtocol(true), password)
+	// Utility statements can't take bind parameters, so the password and expiry are interpolated client side.
+	_, err = tx.Exec(ctx, query, append([]interface{}{pgx.QuerySimpleProtocol(true)}, args...)...)
 	if err != nil {
 		return nil, err
 	}
+
+	if opts.Comment != "" {
+		commentQuery := fmt.Sprintf("COMMENT ON ROLE %s IS $1", sanitizedLogin)
+		l.Debug("setting user comment", zap.String("query", commentQuery))
+
+		_, err = tx.Exec(ctx, commentQuery, pgx.QuerySimpleProtocol(true), opts.Comment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, group := range opts.Groups {
+		grantQuery := fmt.Sprintf("GRANT %s TO %s", pgx.Identifier{group}.Sanitize(), sanitizedLogin)
+		l.Debug("adding user to group", zap.String("query", grantQuery))
+
+		_, err = tx.Exec(ctx, grantQuery)
+		if err != nil {
+			return nil, fmt.Errorf("error adding user to group %s: %w", group, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
 	return c.GetRoleByName(ctx, login)
 }
 
 func (c *Client) ChangePassword(ctx context.Context, userName string, password string) (*RoleModel, error) {
+	if err := c.CheckProtectedRole(userName); err != nil {
+		return nil, err
+	}
+
 	l := ctxzap.Extract(ctx)
 
 	if userName == "" {
@@ -472,6 +1111,104 @@ func (c *Client) ChangePassword(ctx context.Context, userName string, password s
 	return c.GetRoleByName(ctx, userName)
 }
 
+// GetRoleIDsByName returns the IDs of the named roles. Names of roles that don't exist are left out.
+func (c *Client) GetRoleIDsByName(ctx context.Context, roleNames []string) (map[string]int64, error) {
+	rows, err := c.db.Query(ctx, `SELECT "rolname", "oid"::int FROM "pg_catalog"."pg_roles" WHERE "rolname" = ANY($1)`, roleNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var id int64
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		ret[name] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// LookupRoleEmails runs a user-supplied query mapping role names to email addresses. The query is passed the role
+// names as a text[] in $1 and must return the role name and its email, in that order.
+func (c *Client) LookupRoleEmails(ctx context.Context, query string, roleNames []string) (map[string]string, error) {
+	l := ctxzap.Extract(ctx)
+	l.Debug("looking up role emails", zap.String("query", query), zap.Int("roles", len(roleNames)))
+
+	rows, err := c.db.Query(ctx, query, roleNames)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up role emails: %w", err)
+	}
+	defer rows.Close()
+
+	ret := make(map[string]string)
+	for rows.Next() {
+		var roleName, email *string
+		if err := rows.Scan(&roleName, &email); err != nil {
+			return nil, fmt.Errorf("error looking up role emails: %w", err)
+		}
+		if roleName == nil || email == nil {
+			continue
+		}
+		ret[*roleName] = *email
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error looking up role emails: %w", err)
+	}
+
+	return ret, nil
+}
+
+// roleMembersQuery returns the query listing the members of the role passed as $1, together with the
+// options of each membership. Before PostgreSQL 16 INHERIT follows the member's rolinherit and every member
+// can SET ROLE.
+func (c *Client) roleMembersQuery(ctx context.Context) (string, error) {
+	supported, err := c.SupportsMembershipOptions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	membershipOptions := `r."rolinherit" AS "inherit_option",
+       true AS "set_option"`
+	if supported {
+		membershipOptions = `EXISTS(SELECT 1
+              FROM "pg_catalog"."pg_auth_members" am
+              WHERE am."roleid" = $1
+                AND am."member" = r."oid"
+                AND am."inherit_option") AS "inherit_option",
+       EXISTS(SELECT 1
+              FROM "pg_catalog"."pg_auth_members" am
+              WHERE am."roleid" = $1
+                AND am."member" = r."oid"
+                AND am."set_option") AS "set_option"`
+	}
+
+	inheritFrom, err := c.inheritFromColumn(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return `
+SELECT ` + roleColumns + `,
+       EXISTS(SELECT 1
+              FROM "pg_catalog"."pg_auth_members" am
+              WHERE am."roleid" = $1
+                AND am."member" = r."oid"
+                AND am."admin_option") AS "admin_option",
+       ` + membershipOptions + `,
+       ` + memberOfColumn + `,
+       ` + inheritFrom + `
+FROM "pg_catalog"."pg_roles" r
+WHERE r."oid" IN (SELECT "member" FROM "pg_catalog"."pg_auth_members" WHERE "roleid" = $1)
+`, nil
+}
+
 func (c *Client) ListRoleMembers(ctx context.Context, roleID int64, pager *Pager) ([]*RoleModel, string, error) {
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing role members", zap.Int64("role_id", roleID))
@@ -480,28 +1217,16 @@ func (c *Client) ListRoleMembers(ctx context.Context, roleID int64, pager *Pager
 	if err != nil {
 		return nil, "", err
 	}
+
+	q, err := c.roleMembersQuery(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
 	var args []interface{}
 	sb := &strings.Builder{}
-	_, _ = sb.WriteString(`
-SELECT r."rolname",
-       r."rolsuper",
-       r."rolinherit",
-       r."rolcreaterole",
-       r."rolcreatedb",
-       r."rolcanlogin",
-       r."rolreplication",
-       r."rolconnlimit",
-       r."rolbypassrls",
-       r."oid"::int,
-       m."admin_option",
-       ARRAY(SELECT "roleid"::int
-             FROM "pg_catalog"."pg_auth_members"
-             where member = r."oid") AS "member_of"
-FROM "pg_catalog"."pg_roles" r
-         LEFT JOIN "pg_auth_members" m ON m."member" = r."oid"
-WHERE m."roleid" = $1
-ORDER BY r."rolname"
-`)
+	_, _ = sb.WriteString(q)
+	_, _ = sb.WriteString(`ORDER BY r."rolname" `)
 	args = append(args, roleID)
 	_, _ = sb.WriteString("LIMIT $2 ")
 	args = append(args, limit+1)
@@ -529,6 +1254,27 @@ ORDER BY r."rolname"
 	return ret, nextPageToken, nil
 }
 
+// GetRoleMember returns memberID along with the options of its membership in roleID, or nil if memberID is
+// not a member of roleID.
+func (c *Client) GetRoleMember(ctx context.Context, roleID int64, memberID int64) (*RoleModel, error) {
+	q, err := c.roleMembersQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q += `AND r."oid" = $2`
+
+	member := &RoleModel{}
+	err = pgxscan.Get(ctx, c.db, member, q, roleID, memberID)
+	if err != nil {
+		if pgxscan.NotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
 func (c *Client) ListRoles(ctx context.Context, pager *Pager) ([]*RoleModel, string, error) {
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing roles")
@@ -537,24 +1283,17 @@ func (c *Client) ListRoles(ctx context.Context, pager *Pager) ([]*RoleModel, str
 	if err != nil {
 		return nil, "", err
 	}
+	inheritFrom, err := c.inheritFromColumn(ctx)
+	if err != nil {
+		return nil, "", err
+	}
 	var args []interface{}
 	sb := &strings.Builder{}
 	_, _ = sb.WriteString(`
-SELECT r."rolname",
-       r."rolsuper",
-       r."rolinherit",
-       r."rolcreaterole",
-       r."rolcreatedb",
-       r."rolcanlogin",
-       r."rolreplication",
-       r."rolconnlimit",
-       r."rolbypassrls",
-       r."oid"::int,
+SELECT ` + roleColumns + `,
        m."admin_option",
-       ARRAY(SELECT "roleid"::int
-             FROM "pg_catalog"."pg_auth_members"
-             where member = r."oid")
-           AS "member_of"
+       ` + memberOfColumn + `,
+       ` + inheritFrom + `
 FROM "pg_catalog"."pg_roles" r
          LEFT JOIN "pg_auth_members" m ON m."member" = r."oid"
 ORDER BY "rolname"
@@ -0,0 +1,336 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestAlterRoleAttribute(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	err = client.AlterRoleAttribute(ctx, container.Role(), RoleAttributeCreateDb, true)
+	require.NoError(t, err)
+
+	role, err := client.GetRoleByName(ctx, container.Role())
+	require.NoError(t, err)
+	require.True(t, role.CreateDb)
+
+	err = client.AlterRoleAttribute(ctx, container.Role(), RoleAttributeCreateDb, false)
+	require.NoError(t, err)
+
+	role, err = client.GetRoleByName(ctx, container.Role())
+	require.NoError(t, err)
+	require.False(t, role.CreateDb)
+
+	err = client.AlterRoleAttribute(ctx, container.Role(), "LOGIN; DROP TABLE test_table", true)
+	require.Error(t, err)
+}
+
+func TestGrantRoleAdminOption(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	role, err := client.GetRoleByName(ctx, "test_role")
+	require.NoError(t, err)
+
+	memberAdmin := func() (bool, bool) {
+		members, _, err := client.ListRoleMembers(ctx, role.ID, &Pager{Size: 100})
+		require.NoError(t, err)
+		for _, m := range members {
+			if m.Name == "test_user" {
+				return true, m.IsRoleAdmin()
+			}
+		}
+		return false, false
+	}
+
+	isMember, isAdmin := memberAdmin()
+	require.True(t, isMember)
+	require.False(t, isAdmin)
+
+	err = client.GrantRole(ctx, "test_role", "test_user", true)
+	require.NoError(t, err)
+
+	isMember, isAdmin = memberAdmin()
+	require.True(t, isMember)
+	require.True(t, isAdmin)
+
+	// Revoking the admin option keeps the membership itself.
+	err = client.RevokeRole(ctx, "test_role", "test_user", true)
+	require.NoError(t, err)
+
+	isMember, isAdmin = memberAdmin()
+	require.True(t, isMember)
+	require.False(t, isAdmin)
+
+	err = client.RevokeRole(ctx, "test_role", "test_user", false)
+	require.NoError(t, err)
+
+	isMember, _ = memberAdmin()
+	require.False(t, isMember)
+}
+
+func TestRoleMembershipOptions(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	role, err := client.GetRoleByName(ctx, "test_role")
+	require.NoError(t, err)
+
+	user, err := client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	require.Contains(t, user.InheritFrom, role.ID)
+
+	member, err := client.GetRoleMember(ctx, role.ID, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, member)
+	require.True(t, member.InheritsRole())
+	require.True(t, member.CanSetRole())
+
+	supported, err := client.SupportsMembershipOptions(ctx)
+	require.NoError(t, err)
+
+	if !supported {
+		// Without per-membership options, inheritance follows the member's INHERIT attribute.
+		_, err = client.db.Exec(ctx, `ALTER ROLE "test_user" NOINHERIT`)
+		require.NoError(t, err)
+
+		user, err = client.GetRoleByName(ctx, "test_user")
+		require.NoError(t, err)
+		require.Empty(t, user.InheritFrom)
+		return
+	}
+
+	err = client.GrantRoleWithOptions(ctx, "test_role", "test_user", false, true)
+	require.NoError(t, err)
+
+	member, err = client.GetRoleMember(ctx, role.ID, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, member)
+	require.False(t, member.InheritsRole())
+	require.True(t, member.CanSetRole())
+
+	user, err = client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	require.NotContains(t, user.InheritFrom, role.ID)
+
+	err = client.RevokeRoleOption(ctx, "test_role", "test_user", RoleOptionSet)
+	require.NoError(t, err)
+
+	member, err = client.GetRoleMember(ctx, role.ID, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, member)
+	require.False(t, member.CanSetRole())
+}
+
+func TestReassignOwned(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	err = client.CreateRole(ctx, "leaving_role")
+	require.NoError(t, err)
+
+	err = client.SetTableOwner(ctx, "public", "test_table", "leaving_role")
+	require.NoError(t, err)
+
+	// The owned table blocks deleting the role until it is reassigned.
+	err = client.DeleteRole(ctx, "leaving_role")
+	require.Error(t, err)
+
+	reassigned, err := client.ReassignOwned(ctx, "leaving_role", container.Role(), true)
+	require.NoError(t, err)
+	require.Positive(t, reassigned["pg_class"])
+
+	err = client.DeleteRole(ctx, "leaving_role")
+	require.NoError(t, err)
+}
+
+func TestCleanupRoleGrants(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	err = client.CreateRole(ctx, "cleanup_role")
+	require.NoError(t, err)
+
+	_, err = client.db.Exec(ctx, `ALTER DEFAULT PRIVILEGES IN SCHEMA "public" GRANT SELECT ON TABLES TO "cleanup_role"`)
+	require.NoError(t, err)
+
+	err = client.GrantTable(ctx, "public", "test_table", "cleanup_role", "SELECT", false)
+	require.NoError(t, err)
+
+	cleanup, err := client.CleanupRoleGrants(ctx, "cleanup_role")
+	require.NoError(t, err)
+	require.NoError(t, cleanup.Err)
+	require.Contains(t, cleanup.Schemas, "public")
+	require.Equal(t, 1, cleanup.DefaultPrivileges)
+
+	// Without the default privileges the role has nothing left depending on it.
+	_, err = client.db.Exec(ctx, `DROP ROLE "cleanup_role"`)
+	require.NoError(t, err)
+}
+
+func TestDisableEnableRole(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	// Roles the connector didn't disable are refused.
+	err = client.EnableRole(ctx, "test_user")
+	require.Error(t, err)
+
+	_, err = client.db.Exec(ctx, `ALTER ROLE "test_user" VALID UNTIL '2030-01-01 00:00:00+00'`)
+	require.NoError(t, err)
+
+	_, err = client.DisableRole(ctx, "test_user", true)
+	require.NoError(t, err)
+
+	// Disabling it again doesn't lose the state it had before.
+	_, err = client.DisableRole(ctx, "test_user", true)
+	require.NoError(t, err)
+
+	role, err := client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	require.False(t, role.CanLogin)
+	require.True(t, role.IsExpired(time.Now()))
+
+	err = client.EnableRole(ctx, "test_user")
+	require.NoError(t, err)
+
+	role, err = client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	require.True(t, role.CanLogin)
+	require.NotNil(t, role.ValidUntil)
+	require.True(t, role.ValidUntil.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	err = client.EnableRole(ctx, "test_user")
+	require.Error(t, err)
+
+	// A role that couldn't log in before it was disabled can't after it is enabled either.
+	err = client.CreateRole(ctx, "nologin_role")
+	require.NoError(t, err)
+
+	_, err = client.DisableRole(ctx, "nologin_role", false)
+	require.NoError(t, err)
+
+	err = client.EnableRole(ctx, "nologin_role")
+	require.NoError(t, err)
+
+	role, err = client.GetRoleByName(ctx, "nologin_role")
+	require.NoError(t, err)
+	require.False(t, role.CanLogin)
+	require.Nil(t, role.ValidUntil)
+}
+
+func TestRoleProfileColumns(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	_, err = client.db.Exec(ctx, `ALTER ROLE "test_user" CONNECTION LIMIT 3 VALID UNTIL '2030-01-01'`)
+	require.NoError(t, err)
+	_, err = client.db.Exec(ctx, `ALTER ROLE "test_user" SET "work_mem" = '64MB'`)
+	require.NoError(t, err)
+	_, err = client.db.Exec(ctx, `COMMENT ON ROLE "test_user" IS 'reporting service'`)
+	require.NoError(t, err)
+
+	role, err := client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	require.Equal(t, 3, role.ConnectionLimit)
+	require.NotNil(t, role.ValidUntil)
+	require.NotNil(t, role.Comment)
+	require.Equal(t, "reporting service", *role.Comment)
+	require.Equal(t, []string{"work_mem=64MB"}, role.Settings["*"])
+}
+
+func TestCreateUserWithOptions(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	limit := 2
+	validUntil := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	role, err := client.CreateUserWithOptions(ctx, "cert_user", &CreateUserOptions{
+		Groups:          []string{"test_role"},
+		ConnectionLimit: &limit,
+		ValidUntil:      &validUntil,
+	})
+	require.NoError(t, err)
+	require.True(t, role.CanLogin)
+	require.Equal(t, 2, role.ConnectionLimit)
+	require.NotNil(t, role.ValidUntil)
+	require.True(t, role.ValidUntil.Equal(validUntil))
+
+	group, err := client.GetRoleByName(ctx, "test_role")
+	require.NoError(t, err)
+
+	member, err := client.GetRoleMember(ctx, group.ID, role.ID)
+	require.NoError(t, err)
+	require.NotNil(t, member)
+
+	// A group that doesn't exist rolls back the whole creation.
+	_, err = client.CreateUserWithOptions(ctx, "broken_user", &CreateUserOptions{
+		Password: "test_password",
+		Groups:   []string{"missing_group"},
+	})
+	require.Error(t, err)
+
+	_, err = client.GetRoleByName(ctx, "broken_user")
+	require.Error(t, err)
+}
+
+func TestLookupRoleEmails(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	_, err = client.db.Exec(ctx, `CREATE TABLE "people" ("login" text, "email" text)`)
+	require.NoError(t, err)
+	_, err = client.db.Exec(ctx, `INSERT INTO "people" VALUES ('test_user', 'test.user@example.com'), ('someone_else', 'x@example.com')`)
+	require.NoError(t, err)
+
+	emails, err := client.LookupRoleEmails(ctx, `SELECT "login", "email" FROM "people" WHERE "login" = ANY($1)`, []string{"test_user", "test_role"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"test_user": "test.user@example.com"}, emails)
+
+	role, err := client.GetRoleByName(ctx, "test_user")
+	require.NoError(t, err)
+	require.Empty(t, role.SecurityLabels)
+}
@@ -5,11 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
 )
 
 type SchemaModel struct {
@@ -17,6 +18,8 @@ type SchemaModel struct {
 	Name    string   `db:"nspname"`
 	OwnerID int64    `db:"nspowner"`
 	ACLs    []string `db:"nspacl"`
+
+	explodedACL
 }
 
 func (t *SchemaModel) GetOwnerID() int64 {
@@ -41,12 +44,13 @@ func (c *Client) GetSchema(ctx context.Context, schemaID int64) (*SchemaModel, e
 	q := `
 SELECT "oid"::int, "nspname",
        "nspowner",
-       "nspacl"
+       "nspacl",
+       %s
 FROM "pg_catalog"."pg_namespace"
 WHERE "oid" = $1
 `
 
-	err := pgxscan.Get(ctx, c.db, ret, q, schemaID)
+	err := c.getWithACL(ctx, ret, q, `"nspacl"`, schemaID)
 	if err != nil {
 		return nil, err
 	}
@@ -54,11 +58,49 @@ WHERE "oid" = $1
 	return ret, nil
 }
 
+func (c *Client) GetSchemaByName(ctx context.Context, schemaName string) (*SchemaModel, error) {
+	ret := &SchemaModel{}
+
+	q := `
+SELECT "oid"::int, "nspname",
+       "nspowner",
+       "nspacl"
+FROM "pg_catalog"."pg_namespace"
+WHERE "nspname" = $1
+`
+
+	err := pgxscan.Get(ctx, c.db, ret, q, schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// SchemaHasObjects checks if any object lives in the schema.
+func (c *Client) SchemaHasObjects(ctx context.Context, schemaID int64) (bool, error) {
+	query := `
+SELECT EXISTS(SELECT 1
+              FROM "pg_catalog"."pg_depend"
+              WHERE "refclassid" = 'pg_catalog.pg_namespace'::regclass
+                AND "refobjid" = $1
+                AND "deptype" = 'n')
+`
+
+	var ret bool
+	err := c.db.QueryRow(ctx, query, schemaID).Scan(&ret)
+	if err != nil {
+		return false, err
+	}
+
+	return ret, nil
+}
+
 func (c *Client) ListSchemas(ctx context.Context, pager *Pager) ([]*SchemaModel, string, error) {
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing schemas")
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -70,8 +112,10 @@ SELECT "oid"::int, "nspname",
        "nspacl"
 from "pg_catalog"."pg_namespace"
 `)
+	_, _ = sb.WriteString(`WHERE "oid" > $1::bigint::oid `)
+	args = append(args, after)
 	if len(c.schemaFilter) > 0 {
-		_, _ = sb.WriteString("WHERE ")
+		_, _ = sb.WriteString("AND (")
 		for ii, s := range c.schemaFilter {
 			if ii != 0 {
 				_, _ = sb.WriteString("OR ")
@@ -79,14 +123,11 @@ from "pg_catalog"."pg_namespace"
 			_, _ = sb.WriteString(fmt.Sprintf(`"nspname" = $%d `, len(args)+1))
 			args = append(args, s)
 		}
+		_, _ = sb.WriteString(") ")
 	}
 
-	_, _ = sb.WriteString(fmt.Sprintf("LIMIT $%d ", len(args)+1))
+	_, _ = sb.WriteString(fmt.Sprintf(`ORDER BY "oid" LIMIT $%d`, len(args)+1))
 	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString(fmt.Sprintf("OFFSET $%d ", len(args)+1))
-		args = append(args, offset)
-	}
 
 	var ret []*SchemaModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -99,10 +140,89 @@ from "pg_catalog"."pg_namespace"
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
 }
+
+func (c *Client) GrantSchema(ctx context.Context, schema string, principalName string, privilege string, isGrant bool) error {
+	l := ctxzap.Extract(ctx)
+	l.Debug("granting schema", zap.String("schema", schema), zap.String("principalName", principalName), zap.String("privilege", privilege))
+
+	sanitizedSchema := pgx.Identifier{schema}.Sanitize()
+	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
+	sanitizedPrivilege := sanitizePrivilege(privilege)
+
+	q := fmt.Sprintf("GRANT %s ON SCHEMA %s TO %s", sanitizedPrivilege, sanitizedSchema, sanitizedPrincipalName)
+
+	if isGrant {
+		q += withGrantOptions
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
+
+func (c *Client) RevokeSchema(ctx context.Context, schema string, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
+	l := ctxzap.Extract(ctx)
+	l.Debug("revoking schema", zap.String("schema", schema), zap.String("principalName", principalName), zap.String("privilege", privilege))
+
+	sanitizedSchema := pgx.Identifier{schema}.Sanitize()
+	sanitizedPrincipalName := pgx.Identifier{principalName}.Sanitize()
+	sanitizedPrivilege := sanitizePrivilege(privilege)
+
+	var q string
+
+	if isGrant {
+		q = fmt.Sprintf("REVOKE GRANT OPTION FOR %s ON SCHEMA %s FROM %s", sanitizedPrivilege, sanitizedSchema, sanitizedPrincipalName)
+	} else {
+		q = fmt.Sprintf("REVOKE %s ON SCHEMA %s FROM %s", sanitizedPrivilege, sanitizedSchema, sanitizedPrincipalName)
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
+
+func (c *Client) CreateSchema(ctx context.Context, schemaName string, ownerName string) (*SchemaModel, error) {
+	l := ctxzap.Extract(ctx)
+	l.Debug("creating schema", zap.String("schemaName", schemaName), zap.String("ownerName", ownerName))
+
+	if schemaName == "" {
+		return nil, errors.New("schema name cannot be empty")
+	}
+
+	q := fmt.Sprintf("CREATE SCHEMA %s", pgx.Identifier{schemaName}.Sanitize())
+	if ownerName != "" {
+		q += fmt.Sprintf(" AUTHORIZATION %s", pgx.Identifier{ownerName}.Sanitize())
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetSchemaByName(ctx, schemaName)
+}
+
+func (c *Client) DeleteSchema(ctx context.Context, schemaName string, cascade bool) error {
+	l := ctxzap.Extract(ctx)
+	l.Debug("deleting schema", zap.String("schemaName", schemaName), zap.Bool("cascade", cascade))
+
+	q := fmt.Sprintf("DROP SCHEMA %s", pgx.Identifier{schemaName}.Sanitize())
+	if cascade {
+		q += " CASCADE"
+	}
+
+	_, err := c.db.Exec(ctx, q)
+	return err
+}
+
+func (c *Client) SetSchemaOwner(ctx context.Context, schema string, ownerName string) error {
+	return c.alterOwner(ctx, "SCHEMA", pgx.Identifier{schema}.Sanitize(), ownerName)
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/conductorone/baton-postgresql/pkg/testutil"
+)
+
+func TestSchemaGrantRevoke(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	// Is grant true
+	err = client.GrantSchema(ctx, "public", container.Role(), Usage.Name(), true)
+	require.NoError(t, err)
+
+	err = client.RevokeSchema(ctx, "public", container.Role(), Usage.Name(), true)
+	require.NoError(t, err)
+
+	// is grant false
+	err = client.GrantSchema(ctx, "public", container.Role(), Usage.Name(), false)
+	require.NoError(t, err)
+
+	err = client.RevokeSchema(ctx, "public", container.Role(), Usage.Name(), false)
+	require.NoError(t, err)
+
+	// revoke without grant
+	err = client.RevokeSchema(ctx, "public", container.Role(), Usage.Name(), false)
+	require.NoError(t, err)
+
+	err = client.RevokeSchema(ctx, "public", container.Role(), Usage.Name(), true)
+	require.NoError(t, err)
+}
+
+func TestSchemaCreateDelete(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	schema, err := client.CreateSchema(ctx, "test_schema", container.Role())
+	require.NoError(t, err)
+	require.Equal(t, "test_schema", schema.Name)
+
+	_, err = container.Db().Exec(ctx, "CREATE TABLE test_schema.test_schema_table (id INTEGER)")
+	require.NoError(t, err)
+
+	hasObjects, err := client.SchemaHasObjects(ctx, schema.ID)
+	require.NoError(t, err)
+	require.True(t, hasObjects)
+
+	// dropping a non-empty schema requires cascade
+	err = client.DeleteSchema(ctx, schema.Name, false)
+	require.Error(t, err)
+
+	err = client.DeleteSchema(ctx, schema.Name, true)
+	require.NoError(t, err)
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v4"
@@ -21,6 +20,8 @@ type SequenceModel struct {
 	Schema  string   `db:"nspname"`
 	OwnerID int64    `db:"relowner"`
 	ACLs    []string `db:"relacl"`
+
+	explodedACL
 }
 
 func (t *SequenceModel) GetOwnerID() int64 {
@@ -41,7 +42,8 @@ func (t *SequenceModel) DefaultPrivileges() PrivilegeSet {
 
 func (c *Client) getClassQuery(ctx context.Context) string {
 	q := `
-SELECT DISTINCT c."oid"::int, c."relname", c."relowner"::int, n."nspname", c."relacl"
+SELECT DISTINCT c."oid"::int, c."relname", c."relowner"::int, n."nspname", c."relacl",
+       %s
 FROM pg_class c
          LEFT JOIN pg_namespace n ON n."oid" = c."relnamespace"
 WHERE c."oid" = $1
@@ -54,7 +56,7 @@ func (c *Client) GetSequence(ctx context.Context, sequenceID int64) (*SequenceMo
 
 	q := c.getClassQuery(ctx)
 
-	err := pgxscan.Get(ctx, c.db, ret, q, sequenceID)
+	err := c.getWithACL(ctx, ret, q, `c."relacl"`, sequenceID)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +68,7 @@ func (c *Client) ListSequences(ctx context.Context, schemaID int64, pager *Pager
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing sequences")
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -81,12 +83,10 @@ WHERE n."oid" = $1
 `)
 
 	args = append(args, schemaID)
-	_, _ = sb.WriteString("LIMIT $2 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $3")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`  AND c."oid" > $2::bigint::oid
+ORDER BY c."oid"
+LIMIT $3`)
+	args = append(args, after, limit+1)
 
 	var ret []*SequenceModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -99,9 +99,8 @@ WHERE n."oid" = $1
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
@@ -127,6 +126,10 @@ func (c *Client) GrantSequence(ctx context.Context, schema, sequenceName string,
 }
 
 func (c *Client) RevokeSequence(ctx context.Context, schema, sequenceName string, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 	l.Debug("revoking sequence", zap.String("principalName", principalName), zap.String("privilege", privilege))
 
@@ -146,3 +149,7 @@ func (c *Client) RevokeSequence(ctx context.Context, schema, sequenceName string
 	_, err := c.db.Exec(ctx, q)
 	return err
 }
+
+func (c *Client) SetSequenceOwner(ctx context.Context, schema string, sequenceName string, ownerName string) error {
+	return c.alterOwner(ctx, "SEQUENCE", pgx.Identifier{schema, sequenceName}.Sanitize(), ownerName)
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v4"
@@ -21,6 +20,8 @@ type TableModel struct {
 	Schema  string   `db:"nspname"`
 	OwnerID int64    `db:"relowner"`
 	ACLs    []string `db:"relacl"`
+
+	explodedACL
 }
 
 func (t *TableModel) GetOwnerID() int64 {
@@ -44,7 +45,7 @@ func (c *Client) GetTable(ctx context.Context, tableID int64) (*TableModel, erro
 
 	q := c.getClassQuery(ctx)
 
-	err := pgxscan.Get(ctx, c.db, ret, q, tableID)
+	err := c.getWithACL(ctx, ret, q, `c."relacl"`, tableID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +57,7 @@ func (c *Client) ListTables(ctx context.Context, schemaName string, pager *Pager
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing tables")
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -71,12 +72,10 @@ WHERE n."nspname" = $1
 `)
 
 	args = append(args, schemaName)
-	_, _ = sb.WriteString("LIMIT $2 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $3")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`  AND c."oid" > $2::bigint::oid
+ORDER BY c."oid"
+LIMIT $3`)
+	args = append(args, after, limit+1)
 
 	var ret []*TableModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -89,9 +88,8 @@ WHERE n."nspname" = $1
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
@@ -117,6 +115,10 @@ func (c *Client) GrantTable(ctx context.Context, schema string, tableName string
 }
 
 func (c *Client) RevokeTable(ctx context.Context, schema string, tableName string, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 	l.Debug("revoking table", zap.String("principalName", principalName), zap.String("privilege", privilege))
 
@@ -136,3 +138,7 @@ func (c *Client) RevokeTable(ctx context.Context, schema string, tableName strin
 	_, err := c.db.Exec(ctx, q)
 	return err
 }
+
+func (c *Client) SetTableOwner(ctx context.Context, schema string, tableName string, ownerName string) error {
+	return c.alterOwner(ctx, "TABLE", pgx.Identifier{schema, tableName}.Sanitize(), ownerName)
+}
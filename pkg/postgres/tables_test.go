@@ -38,3 +38,23 @@ func TestTableGrantRevoke(t *testing.T) {
 	err = client.RevokeTable(ctx, "public", "test_table", container.Role(), Select.Name(), true)
 	require.NoError(t, err)
 }
+
+func TestTableSetOwner(t *testing.T) {
+	ctx := context.Background()
+
+	container := testutil.SetupPostgresContainer(ctx, t)
+
+	client, err := New(ctx, container.Dsn())
+	require.NoError(t, err)
+
+	err = client.SetTableOwner(ctx, "public", "test_table_log", container.Role())
+	require.NoError(t, err)
+
+	role, err := client.GetRoleByName(ctx, container.Role())
+	require.NoError(t, err)
+
+	var ownerID int64
+	err = container.Db().QueryRow(ctx, `SELECT "relowner"::int FROM "pg_class" WHERE "relname" = 'test_table_log'`).Scan(&ownerID)
+	require.NoError(t, err)
+	require.Equal(t, role.ID, ownerID)
+}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v4"
@@ -21,6 +20,8 @@ type ViewModel struct {
 	Schema  string   `db:"nspname"`
 	OwnerID int64    `db:"relowner"`
 	ACLs    []string `db:"relacl"`
+
+	explodedACL
 }
 
 func (t *ViewModel) GetOwnerID() int64 {
@@ -44,7 +45,7 @@ func (c *Client) GetView(ctx context.Context, viewID int64) (*ViewModel, error)
 
 	q := c.getClassQuery(ctx)
 
-	err := pgxscan.Get(ctx, c.db, ret, q, viewID)
+	err := c.getWithACL(ctx, ret, q, `c."relacl"`, viewID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +57,7 @@ func (c *Client) ListViews(ctx context.Context, schemaID int64, pager *Pager) ([
 	l := ctxzap.Extract(ctx)
 	l.Debug("listing views")
 
-	offset, limit, err := pager.Parse()
+	after, limit, err := pager.ParseKeyset()
 	if err != nil {
 		return nil, "", err
 	}
@@ -71,12 +72,10 @@ WHERE n."oid" = $1 AND c."relkind" = 'v'
 `)
 	args = append(args, schemaID)
 
-	_, _ = sb.WriteString("LIMIT $2 ")
-	args = append(args, limit+1)
-	if offset > 0 {
-		_, _ = sb.WriteString("OFFSET $3")
-		args = append(args, offset)
-	}
+	_, _ = sb.WriteString(`  AND c."oid" > $2::bigint::oid
+ORDER BY c."oid"
+LIMIT $3`)
+	args = append(args, after, limit+1)
 
 	var ret []*ViewModel
 	err = pgxscan.Select(ctx, c.db, &ret, sb.String(), args...)
@@ -89,9 +88,8 @@ WHERE n."oid" = $1 AND c."relkind" = 'v'
 
 	var nextPageToken string
 	if len(ret) > limit {
-		offset += limit
-		nextPageToken = strconv.Itoa(offset)
 		ret = ret[:limit]
+		nextPageToken = keysetPageToken(oidKey(ret[limit-1].ID))
 	}
 
 	return ret, nextPageToken, nil
@@ -117,6 +115,10 @@ func (c *Client) GrantView(ctx context.Context, schema, viewName string, princip
 }
 
 func (c *Client) RevokeView(ctx context.Context, schema, viewName string, principalName string, privilege string, isGrant bool) error {
+	if err := c.CheckProtectedRole(principalName); err != nil {
+		return err
+	}
+
 	l := ctxzap.Extract(ctx)
 	l.Debug("revoking view", zap.String("principalName", principalName), zap.String("privilege", privilege))
 
@@ -135,3 +137,7 @@ func (c *Client) RevokeView(ctx context.Context, schema, viewName string, princi
 	_, err := c.db.Exec(ctx, q)
 	return err
 }
+
+func (c *Client) SetViewOwner(ctx context.Context, schema string, viewName string, ownerName string) error {
+	return c.alterOwner(ctx, "VIEW", pgx.Identifier{schema, viewName}.Sanitize(), ownerName)
+}